@@ -0,0 +1,328 @@
+// Package config owns the on-disk shape and persistence of the shared
+// ~/.pricetrack.json file: tracked symbols (with optional per-symbol
+// holdings), configured price alerts, and the schema versioning/migration
+// and advisory locking needed for two widget instances to share the file
+// safely. It has no dependency on Qt.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/shopspring/decimal"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/portfolio"
+)
+
+// defaultSymbols is the symbol set a fresh config file is seeded with.
+var defaultSymbols = []string{"BTC", "ETH", "SOL"}
+
+// SymbolConfig describes one tracked row: the symbol to display, when the
+// user wants to pin or mix specific exchanges, which providers to query for
+// it, and an optional Holding that turns the row into a portfolio position.
+// An empty Providers falls back to the widget's default provider set; a nil
+// Holding means the row is tracked as a bare price.
+type SymbolConfig struct {
+	Symbol    string             `json:"symbol"`
+	Providers []string           `json:"providers,omitempty"`
+	Holding   *portfolio.Holding `json:"holding,omitempty"`
+}
+
+// currentConfigVersion is the schema version this build writes. Config
+// files at an older version are migrated up to it on load; see
+// migrateConfigUp.
+const currentConfigVersion = 2
+
+// AlertOperator is the comparison an Alert fires on.
+type AlertOperator string
+
+const (
+	// OperatorGTE fires once the price is at or above TargetPrice.
+	OperatorGTE AlertOperator = ">="
+	// OperatorLTE fires once the price is at or below TargetPrice.
+	OperatorLTE AlertOperator = "<="
+	// OperatorCrosses fires the first time the price moves from one side
+	// of TargetPrice to the other, in either direction.
+	OperatorCrosses AlertOperator = "crosses"
+)
+
+// Alert is a user-configured watch on one symbol's price.
+type Alert struct {
+	ID          string          `json:"id"`
+	Symbol      string          `json:"symbol"`
+	TargetPrice decimal.Decimal `json:"target_price"`
+	Operator    AlertOperator   `json:"operator"`
+	Enabled     bool            `json:"enabled"`
+	Triggered   bool            `json:"triggered"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// GenerateAlertID returns a unique ID for a new alert, suitable for use in
+// Alert.ID.
+func GenerateAlertID() string {
+	return fmt.Sprintf("alert-%d", time.Now().UnixNano())
+}
+
+// ConfigFile is the on-disk shape of ~/.pricetrack.json: a schema version
+// (see currentConfigVersion), tracked symbols, and any price alerts the
+// user has configured for them. Providers is round-tripped verbatim but
+// not yet read by the widget -- it's reserved so a future release can add
+// global provider settings without another format migration. UI is
+// likewise round-tripped verbatim, but its known fields are parsed by
+// LoadUISettings.
+type ConfigFile struct {
+	Version   int             `json:"version"`
+	Symbols   []SymbolConfig  `json:"symbols"`
+	Providers json.RawMessage `json:"providers,omitempty"`
+	Alerts    []Alert         `json:"alerts,omitempty"`
+	UI        json.RawMessage `json:"ui,omitempty"`
+}
+
+// UISettings holds the persisted UI preferences parsed from
+// ConfigFile.UI. A zero value means "use the widget's built-in default"
+// for every field.
+type UISettings struct {
+	// SparklineSamples is how many samples a "last N ticks" sparkline
+	// keeps. Zero means the widget's own default.
+	SparklineSamples int `json:"sparkline_samples,omitempty"`
+}
+
+// LoadUISettings reads the UI preferences persisted in the shared config
+// file. A missing file, or one written before UI settings existed, simply
+// has no preferences yet, which isn't an error.
+func LoadUISettings() (UISettings, error) {
+	cf, err := ReadConfigFile(FilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return UISettings{}, nil
+		}
+		return UISettings{}, fmt.Errorf("load ui settings: %w", err)
+	}
+	if len(cf.UI) == 0 {
+		return UISettings{}, nil
+	}
+	var settings UISettings
+	if err := json.Unmarshal(cf.UI, &settings); err != nil {
+		return UISettings{}, fmt.Errorf("parse ui settings: %w", err)
+	}
+	return settings, nil
+}
+
+// FilePath returns the path of the shared ~/.pricetrack.json config file
+// that symbols and alerts are both persisted to.
+func FilePath() string {
+	homePath, _ := os.UserHomeDir()
+	return fmt.Sprintf("%s/.pricetrack.json", homePath)
+}
+
+// LoadSymbols loads the symbol configs to track from the config file. If
+// the config file does not exist, it creates a new one with default
+// symbols. It returns the symbol configs to track.
+func LoadSymbols() ([]SymbolConfig, error) {
+	path := FilePath()
+	cf, err := ReadConfigFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("Config file not found, creating a new one with default symbols: %v\n", defaultSymbols)
+			CreateDefaultConfig(path)
+			return DefaultSymbolConfigs(), nil
+		}
+		// If there is an error opening the config file, print the error and return default symbols
+		fmt.Println("Error opening config file:", err)
+		return DefaultSymbolConfigs(), nil
+	}
+	return cf.Symbols, nil
+}
+
+// DefaultSymbolConfigs wraps defaultSymbols as SymbolConfigs with no
+// provider override, so they use the widget's default provider set.
+func DefaultSymbolConfigs() []SymbolConfig {
+	configs := make([]SymbolConfig, 0, len(defaultSymbols))
+	for _, symbol := range defaultSymbols {
+		configs = append(configs, SymbolConfig{Symbol: symbol})
+	}
+	return configs
+}
+
+// CreateDefaultConfig creates a new config file with default symbols.
+func CreateDefaultConfig(path string) error {
+	return withConfigLock(path, func() error {
+		return writeConfigFile(path, ConfigFile{Version: currentConfigVersion, Symbols: DefaultSymbolConfigs()})
+	})
+}
+
+// SaveSymbols rewrites the shared config file with the given symbol
+// configs, preserving whatever alerts are already there.
+func SaveSymbols(configs []SymbolConfig) error {
+	path := FilePath()
+	return withConfigLock(path, func() error {
+		cf, err := ReadConfigFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("save symbols: %w", err)
+		}
+		if cf.Version == 0 {
+			cf.Version = currentConfigVersion
+		}
+		cf.Symbols = configs
+		return writeConfigFile(path, cf)
+	})
+}
+
+// LoadAlerts reads the alerts persisted alongside the symbol list. A
+// missing file or one written in the legacy symbols-only format simply has
+// no alerts yet, which isn't an error.
+func LoadAlerts() ([]Alert, error) {
+	cf, err := ReadConfigFile(FilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("load alerts: %w", err)
+	}
+	return cf.Alerts, nil
+}
+
+// SaveAlerts rewrites the shared config file with the given alerts,
+// preserving whatever symbols are already there.
+func SaveAlerts(alerts []Alert) error {
+	path := FilePath()
+	return withConfigLock(path, func() error {
+		cf, err := ReadConfigFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("save alerts: %w", err)
+		}
+		if cf.Version == 0 {
+			cf.Version = currentConfigVersion
+		}
+		cf.Alerts = alerts
+		return writeConfigFile(path, cf)
+	})
+}
+
+// configLockPath returns the advisory lock file used to serialize
+// read-modify-write cycles against the config file at path across
+// multiple running instances of this widget.
+func configLockPath(path string) string {
+	return path + ".lock"
+}
+
+// withConfigLock runs fn while holding an exclusive advisory lock on
+// path's lock file, so two widget instances editing the same config file
+// at once queue instead of one silently clobbering the other's write. It's
+// wrapped around every read-modify-write cycle (SaveSymbols, SaveAlerts,
+// CreateDefaultConfig), not just writeConfigFile itself, since the race
+// that corrupts state is between the read and the write, not just the
+// write.
+func withConfigLock(path string, fn func() error) error {
+	lock := flock.New(configLockPath(path))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("lock config file: %w", err)
+	}
+	defer lock.Unlock()
+	return fn()
+}
+
+// ReadConfigFile reads and parses the shared config file, migrating it up
+// to currentConfigVersion if it's in an older format.
+func ReadConfigFile(path string) (ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ConfigFile{}, err
+	}
+	cf, err := migrateConfigJSON(data)
+	if err != nil {
+		return ConfigFile{}, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return cf, nil
+}
+
+// migrateConfigJSON parses raw config JSON in any format this widget has
+// ever written and migrates it up to currentConfigVersion. It accepts the
+// current versioned object form (`{"version": 2, "symbols": [...], ...}`),
+// the unversioned object form written before schema versioning existed
+// (`{"symbols": [...], "alerts": [...]}`), the intermediate plain
+// array-of-objects form (`[{"symbol": "BTC"}, ...]`), and the original
+// legacy plain string array form (`["BTC", "ETH"]`). It returns an error
+// only once raw matches none of those shapes, so a genuinely malformed
+// config file is reported instead of silently falling back to an empty
+// symbol list.
+func migrateConfigJSON(raw []byte) (ConfigFile, error) {
+	var cf ConfigFile
+	if err := json.Unmarshal(raw, &cf); err == nil && (cf.Version != 0 || cf.Symbols != nil || cf.Alerts != nil) {
+		if cf.Version == 0 {
+			cf.Version = 1 // unversioned object form predates the version field
+		}
+		return migrateConfigUp(cf), nil
+	}
+
+	var configs []SymbolConfig
+	if err := json.Unmarshal(raw, &configs); err == nil {
+		return migrateConfigUp(ConfigFile{Version: 1, Symbols: configs}), nil
+	}
+
+	var symbols []string
+	if err := json.Unmarshal(raw, &symbols); err != nil {
+		return ConfigFile{}, fmt.Errorf("unrecognized config format: %w", err)
+	}
+	configs = make([]SymbolConfig, 0, len(symbols))
+	for _, symbol := range symbols {
+		configs = append(configs, SymbolConfig{Symbol: symbol})
+	}
+	return migrateConfigUp(ConfigFile{Version: 1, Symbols: configs}), nil
+}
+
+// migrateConfigUp steps cf forward one version at a time until it reaches
+// currentConfigVersion. There's only one migration today -- v1 to v2 is a
+// no-op beyond stamping the version, since v1 configs already parse into
+// the same fields v2 uses -- but stepping one version at a time keeps a
+// future migration additive instead of requiring every past version to
+// migrate directly to the latest.
+func migrateConfigUp(cf ConfigFile) ConfigFile {
+	for cf.Version < currentConfigVersion {
+		switch cf.Version {
+		case 1:
+			cf.Version = 2
+		default:
+			cf.Version = currentConfigVersion
+		}
+	}
+	return cf
+}
+
+// writeConfigFile marshals cf and writes it to path. It writes to a
+// temporary file in the same directory first and renames it into place, so
+// a crash or concurrent read never observes a partially-written config.
+// Callers that read-then-write should hold withConfigLock around the whole
+// cycle; this alone only protects against a torn read of the file itself.
+func writeConfigFile(path string, cf ConfigFile) error {
+	data, err := json.MarshalIndent(cf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp config file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp config file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("chmod temp config file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename temp config file into place: %w", err)
+	}
+	return nil
+}
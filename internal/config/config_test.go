@@ -0,0 +1,152 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMigrateConfigJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantVersion int
+		wantSymbols []string
+		wantErr     bool
+	}{
+		{
+			name:        "legacy plain string array",
+			input:       `["BTC", "ETH"]`,
+			wantVersion: currentConfigVersion,
+			wantSymbols: []string{"BTC", "ETH"},
+		},
+		{
+			name:        "legacy array of objects",
+			input:       `[{"symbol": "BTC", "providers": ["binance"]}]`,
+			wantVersion: currentConfigVersion,
+			wantSymbols: []string{"BTC"},
+		},
+		{
+			name:        "unversioned object form",
+			input:       `{"symbols": [{"symbol": "SOL"}], "alerts": []}`,
+			wantVersion: currentConfigVersion,
+			wantSymbols: []string{"SOL"},
+		},
+		{
+			name:        "current versioned object form",
+			input:       `{"version": 2, "symbols": [{"symbol": "DOGE"}]}`,
+			wantVersion: currentConfigVersion,
+			wantSymbols: []string{"DOGE"},
+		},
+		{
+			name:    "malformed JSON",
+			input:   `{"symbols": [`,
+			wantErr: true,
+		},
+		{
+			name:    "well-formed JSON that matches no recognized shape",
+			input:   `42`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cf, err := migrateConfigJSON([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cf.Version != tt.wantVersion {
+				t.Errorf("version = %d, want %d", cf.Version, tt.wantVersion)
+			}
+			symbols := make([]string, len(cf.Symbols))
+			for i, cfg := range cf.Symbols {
+				symbols[i] = cfg.Symbol
+			}
+			if !reflect.DeepEqual(symbols, tt.wantSymbols) {
+				t.Errorf("symbols = %v, want %v", symbols, tt.wantSymbols)
+			}
+		})
+	}
+}
+
+func TestReadConfigFileMigratesLegacyFileOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricetrack.json")
+	if err := os.WriteFile(path, []byte(`["BTC"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cf, err := ReadConfigFile(path)
+	if err != nil {
+		t.Fatalf("ReadConfigFile: %v", err)
+	}
+	if cf.Version != currentConfigVersion {
+		t.Errorf("version = %d, want %d", cf.Version, currentConfigVersion)
+	}
+	if len(cf.Symbols) != 1 || cf.Symbols[0].Symbol != "BTC" {
+		t.Errorf("symbols = %+v, want a single BTC entry", cf.Symbols)
+	}
+}
+
+func TestReadConfigFileMalformedJSONReturnsError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricetrack.json")
+	if err := os.WriteFile(path, []byte(`{not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadConfigFile(path); err == nil {
+		t.Error("expected an error for malformed config JSON, not a silently empty config")
+	}
+}
+
+// TestWithConfigLockSerializesConcurrentWriters checks that two critical
+// sections guarded by withConfigLock on the same path never run at the
+// same time, the property that keeps two widget instances' read-modify-
+// write cycles from interleaving and clobbering each other's save.
+func TestWithConfigLockSerializesConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricetrack.json")
+
+	const writers = 5
+	var mu sync.Mutex
+	var active, maxActive int
+	var wg sync.WaitGroup
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := withConfigLock(path, func() error {
+				mu.Lock()
+				active++
+				if active > maxActive {
+					maxActive = active
+				}
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				active--
+				mu.Unlock()
+				return nil
+			})
+			if err != nil {
+				t.Errorf("withConfigLock: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxActive != 1 {
+		t.Errorf("max concurrent holders of the config lock = %d, want 1", maxActive)
+	}
+}
@@ -0,0 +1,206 @@
+// Package portfolio implements average-cost holdings accounting and
+// position valuation for the widget's optional per-symbol positions. It has
+// no dependency on Qt or the on-disk config format, so the accounting math
+// can be built and tested in isolation from the GUI.
+package portfolio
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Holding is a user's position in a tracked symbol: how much they hold,
+// the total amount they paid for it, and which currency that cost is
+// denominated in. A tracked symbol with no Holding is shown as a bare
+// price, not a position.
+type Holding struct {
+	Amount        decimal.Decimal `json:"amount"`
+	CostBasis     decimal.Decimal `json:"cost_basis"`
+	QuoteCurrency string          `json:"quote_currency,omitempty"`
+}
+
+// CostCurrency returns the currency h's CostBasis is denominated in,
+// defaulting to quoteCurrency when the holding doesn't override it.
+func (h Holding) CostCurrency(quoteCurrency string) string {
+	if h.QuoteCurrency == "" {
+		return quoteCurrency
+	}
+	return h.QuoteCurrency
+}
+
+// PositionValue is a Holding's computed figures at a point in time: its
+// current value and P/L against its cost basis (all-time), plus P/L
+// against a reference price from earlier in the sparkline's window (e.g.
+// 24h ago).
+type PositionValue struct {
+	Value decimal.Decimal
+
+	PL        decimal.Decimal
+	PLPercent decimal.Decimal
+
+	WindowPL        decimal.Decimal
+	WindowPLPercent decimal.Decimal
+}
+
+// ValuePosition prices h at price (quoted in the caller's quote currency),
+// converts it to h's cost currency via fxRate, and computes all-time P/L
+// against h.CostBasis and window P/L against windowPrice (e.g. the earliest
+// sample in the sparkline's 24h window). fxRate should be 1 when
+// h.CostCurrency() already equals the quote currency.
+func ValuePosition(h Holding, price, fxRate, windowPrice decimal.Decimal) PositionValue {
+	value := h.Amount.Mul(price).Mul(fxRate)
+	windowValue := h.Amount.Mul(windowPrice).Mul(fxRate)
+
+	pv := PositionValue{
+		Value:    value,
+		PL:       value.Sub(h.CostBasis),
+		WindowPL: value.Sub(windowValue),
+	}
+	if !h.CostBasis.IsZero() {
+		pv.PLPercent = pv.PL.Div(h.CostBasis).Mul(decimal.NewFromInt(100))
+	}
+	if !windowValue.IsZero() {
+		pv.WindowPLPercent = pv.WindowPL.Div(windowValue).Mul(decimal.NewFromInt(100))
+	}
+	return pv
+}
+
+// SumPositions totals a set of rows' PositionValues into a single
+// portfolio-wide figure. Percentages are derived from the totals rather
+// than averaged per-row, so a large position doesn't get diluted by a
+// small one's percentage swing.
+func SumPositions(values []PositionValue) PositionValue {
+	var total PositionValue
+	var costBasis, windowValue decimal.Decimal
+	for _, v := range values {
+		total.Value = total.Value.Add(v.Value)
+		total.PL = total.PL.Add(v.PL)
+		total.WindowPL = total.WindowPL.Add(v.WindowPL)
+		costBasis = costBasis.Add(v.Value.Sub(v.PL))
+		windowValue = windowValue.Add(v.Value.Sub(v.WindowPL))
+	}
+	if !costBasis.IsZero() {
+		total.PLPercent = total.PL.Div(costBasis).Mul(decimal.NewFromInt(100))
+	}
+	if !windowValue.IsZero() {
+		total.WindowPLPercent = total.WindowPL.Div(windowValue).Mul(decimal.NewFromInt(100))
+	}
+	return total
+}
+
+// ApplyTrade folds a buy or sell of tradeAmount at tradePrice into an
+// existing holding using the average-cost method: a buy (tradeAmount > 0)
+// extends the position and adds to its cost basis; a sell (tradeAmount <
+// 0) shrinks the position and removes a proportional share of the cost
+// basis, realizing the rest as P/L rather than carrying it forward.
+// Selling more than is held clamps the result to an empty position instead
+// of going negative.
+func ApplyTrade(existing Holding, tradeAmount, tradePrice decimal.Decimal) Holding {
+	if tradeAmount.IsZero() {
+		return existing
+	}
+
+	if tradeAmount.IsPositive() {
+		return Holding{
+			Amount:        existing.Amount.Add(tradeAmount),
+			CostBasis:     existing.CostBasis.Add(tradeAmount.Mul(tradePrice)),
+			QuoteCurrency: existing.QuoteCurrency,
+		}
+	}
+
+	sold := tradeAmount.Neg()
+	if sold.GreaterThanOrEqual(existing.Amount) || existing.Amount.IsZero() {
+		return Holding{QuoteCurrency: existing.QuoteCurrency}
+	}
+
+	soldFraction := sold.Div(existing.Amount)
+	return Holding{
+		Amount:        existing.Amount.Sub(sold),
+		CostBasis:     existing.CostBasis.Sub(existing.CostBasis.Mul(soldFraction)),
+		QuoteCurrency: existing.QuoteCurrency,
+	}
+}
+
+// Portfolio owns a set of holdings and the FX rates needed to value any
+// holding whose cost currency isn't its quote currency.
+type Portfolio struct {
+	mu       sync.Mutex
+	quote    string
+	holdings map[string]Holding
+	fxRates  map[string]decimal.Decimal
+}
+
+// New builds a Portfolio that values every holding against quote (e.g.
+// "USDT"), pre-populated with holdings, e.g. loaded from the config file at
+// startup.
+func New(quote string, holdings map[string]Holding) *Portfolio {
+	p := &Portfolio{
+		quote:    quote,
+		holdings: make(map[string]Holding, len(holdings)),
+		fxRates:  make(map[string]decimal.Decimal),
+	}
+	for symbol, h := range holdings {
+		p.holdings[symbol] = h
+	}
+	return p
+}
+
+// Get returns symbol's holding, if any.
+func (p *Portfolio) Get(symbol string) (Holding, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, ok := p.holdings[symbol]
+	return h, ok
+}
+
+// Set records symbol's holding, replacing any existing one.
+func (p *Portfolio) Set(symbol string, h Holding) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.holdings[symbol] = h
+}
+
+// Remove deletes symbol's holding, e.g. once its position has been fully
+// closed out.
+func (p *Portfolio) Remove(symbol string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.holdings, symbol)
+}
+
+// List returns a snapshot of every symbol with a holding.
+func (p *Portfolio) List() map[string]Holding {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[string]Holding, len(p.holdings))
+	for symbol, h := range p.holdings {
+		out[symbol] = h
+	}
+	return out
+}
+
+// SetFXRate records the rate that converts one unit of the portfolio's
+// quote currency into currency, used to value holdings costed in a
+// currency other than the quote currency.
+func (p *Portfolio) SetFXRate(currency string, rate decimal.Decimal) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fxRates[currency] = rate
+}
+
+// FXRate returns the rate that converts one unit of the portfolio's quote
+// currency into currency. It's 1 for the quote currency itself and for any
+// currency no rate has been fetched for yet, so a holding values at its
+// quote-currency price until a refresh populates the real rate.
+func (p *Portfolio) FXRate(currency string) decimal.Decimal {
+	if currency == "" || currency == p.quote {
+		return decimal.NewFromInt(1)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rate, ok := p.fxRates[currency]; ok {
+		return rate
+	}
+	return decimal.NewFromInt(1)
+}
@@ -0,0 +1,146 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// testQuote is the quote currency used across this file's Portfolio tests,
+// standing in for the widget's real quoteCurrency constant.
+const testQuote = "USDT"
+
+func mustDecimal(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return d
+}
+
+func TestValuePositionAllTimePL(t *testing.T) {
+	h := Holding{Amount: mustDecimal(t, "2"), CostBasis: mustDecimal(t, "100")}
+	pv := ValuePosition(h, mustDecimal(t, "60"), decimal.NewFromInt(1), mustDecimal(t, "60"))
+
+	if !pv.Value.Equal(mustDecimal(t, "120")) {
+		t.Errorf("value = %s, want 120", pv.Value)
+	}
+	if !pv.PL.Equal(mustDecimal(t, "20")) {
+		t.Errorf("PL = %s, want 20", pv.PL)
+	}
+	if !pv.PLPercent.Equal(mustDecimal(t, "20")) {
+		t.Errorf("PLPercent = %s, want 20", pv.PLPercent)
+	}
+}
+
+func TestValuePositionZeroCostBasis(t *testing.T) {
+	h := Holding{Amount: mustDecimal(t, "2"), CostBasis: decimal.Zero}
+	pv := ValuePosition(h, mustDecimal(t, "60"), decimal.NewFromInt(1), mustDecimal(t, "60"))
+
+	if !pv.PLPercent.IsZero() {
+		t.Errorf("PLPercent with zero cost basis = %s, want 0 (not a divide-by-zero panic)", pv.PLPercent)
+	}
+}
+
+func TestValuePositionWindowPL(t *testing.T) {
+	h := Holding{Amount: mustDecimal(t, "10"), CostBasis: mustDecimal(t, "500")}
+	// Price rose from 45 (24h ago) to 55 now.
+	pv := ValuePosition(h, mustDecimal(t, "55"), decimal.NewFromInt(1), mustDecimal(t, "45"))
+
+	if !pv.WindowPL.Equal(mustDecimal(t, "100")) {
+		t.Errorf("WindowPL = %s, want 100", pv.WindowPL)
+	}
+	wantPercent := mustDecimal(t, "100").Div(mustDecimal(t, "450")).Mul(mustDecimal(t, "100"))
+	if !pv.WindowPLPercent.Equal(wantPercent) {
+		t.Errorf("WindowPLPercent = %s, want %s", pv.WindowPLPercent, wantPercent)
+	}
+}
+
+func TestValuePositionFXConversion(t *testing.T) {
+	// 1 BTC held, costed in EUR, priced in USDT with a 0.9 USDT->EUR rate.
+	h := Holding{Amount: mustDecimal(t, "1"), CostBasis: mustDecimal(t, "45000"), QuoteCurrency: "EUR"}
+	fxRate := mustDecimal(t, "0.9")
+	pv := ValuePosition(h, mustDecimal(t, "50000"), fxRate, mustDecimal(t, "50000"))
+
+	if !pv.Value.Equal(mustDecimal(t, "45000")) {
+		t.Errorf("value = %s, want 45000 EUR", pv.Value)
+	}
+	if !pv.PL.IsZero() {
+		t.Errorf("PL = %s, want 0 once converted to EUR", pv.PL)
+	}
+}
+
+func TestApplyTradeBuyExtendsPosition(t *testing.T) {
+	existing := Holding{Amount: mustDecimal(t, "1"), CostBasis: mustDecimal(t, "100")}
+	updated := ApplyTrade(existing, mustDecimal(t, "1"), mustDecimal(t, "200"))
+
+	if !updated.Amount.Equal(mustDecimal(t, "2")) {
+		t.Errorf("amount = %s, want 2", updated.Amount)
+	}
+	if !updated.CostBasis.Equal(mustDecimal(t, "300")) {
+		t.Errorf("cost basis = %s, want 300", updated.CostBasis)
+	}
+}
+
+func TestApplyTradePartialSellKeepsAverageCost(t *testing.T) {
+	// Bought 4 for 400 total (avg cost 100 each); sell 1 (a quarter of the
+	// position) and a quarter of the cost basis should go with it.
+	existing := Holding{Amount: mustDecimal(t, "4"), CostBasis: mustDecimal(t, "400")}
+	updated := ApplyTrade(existing, mustDecimal(t, "-1"), mustDecimal(t, "150"))
+
+	if !updated.Amount.Equal(mustDecimal(t, "3")) {
+		t.Errorf("amount = %s, want 3", updated.Amount)
+	}
+	if !updated.CostBasis.Equal(mustDecimal(t, "300")) {
+		t.Errorf("cost basis = %s, want 300", updated.CostBasis)
+	}
+}
+
+func TestApplyTradeSellEverythingClears(t *testing.T) {
+	existing := Holding{Amount: mustDecimal(t, "2"), CostBasis: mustDecimal(t, "200")}
+	updated := ApplyTrade(existing, mustDecimal(t, "-2"), mustDecimal(t, "300"))
+
+	if !updated.Amount.IsZero() || !updated.CostBasis.IsZero() {
+		t.Errorf("got amount=%s costBasis=%s, want a cleared position", updated.Amount, updated.CostBasis)
+	}
+}
+
+func TestApplyTradeSellMoreThanHeldClampsToEmpty(t *testing.T) {
+	existing := Holding{Amount: mustDecimal(t, "1"), CostBasis: mustDecimal(t, "100")}
+	updated := ApplyTrade(existing, mustDecimal(t, "-5"), mustDecimal(t, "300"))
+
+	if !updated.Amount.IsZero() || !updated.CostBasis.IsZero() {
+		t.Errorf("overselling should clamp to empty, got amount=%s costBasis=%s", updated.Amount, updated.CostBasis)
+	}
+}
+
+func TestPortfolioFXRateDefaultsToOne(t *testing.T) {
+	p := New(testQuote, nil)
+
+	if !p.FXRate(testQuote).Equal(decimal.NewFromInt(1)) {
+		t.Errorf("FXRate(quote) = %s, want 1", p.FXRate(testQuote))
+	}
+	if !p.FXRate("EUR").Equal(decimal.NewFromInt(1)) {
+		t.Errorf("FXRate for an unfetched currency = %s, want 1 until a refresh populates it", p.FXRate("EUR"))
+	}
+
+	p.SetFXRate("EUR", mustDecimal(t, "0.9"))
+	if !p.FXRate("EUR").Equal(mustDecimal(t, "0.9")) {
+		t.Errorf("FXRate(EUR) after SetFXRate = %s, want 0.9", p.FXRate("EUR"))
+	}
+}
+
+func TestSumPositions(t *testing.T) {
+	a := ValuePosition(Holding{Amount: mustDecimal(t, "1"), CostBasis: mustDecimal(t, "100")}, mustDecimal(t, "120"), decimal.NewFromInt(1), mustDecimal(t, "110"))
+	b := ValuePosition(Holding{Amount: mustDecimal(t, "2"), CostBasis: mustDecimal(t, "300")}, mustDecimal(t, "140"), decimal.NewFromInt(1), mustDecimal(t, "130"))
+
+	total := SumPositions([]PositionValue{a, b})
+
+	if !total.Value.Equal(mustDecimal(t, "400")) {
+		t.Errorf("total value = %s, want 400", total.Value)
+	}
+	if !total.PL.Equal(mustDecimal(t, "0")) {
+		t.Errorf("total PL = %s, want 0", total.PL)
+	}
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AggregationStrategy controls how an Aggregator combines multiple
+// providers' quotes for the same symbol into a single displayed price.
+type AggregationStrategy int
+
+const (
+	// StrategyMedian takes the median of all successful quotes. It is the
+	// default: cheap to compute and resistant to a single stale/bad feed.
+	StrategyMedian AggregationStrategy = iota
+
+	// StrategyFailover queries every provider in parallel and uses the quote
+	// of whichever healthy provider answers first, rather than combining
+	// values; providers that error are passed over.
+	StrategyFailover
+)
+
+// providerConfig is the per-provider tuning an Aggregator applies on top of
+// a PriceProvider: how long to wait for it and how to treat its failures.
+type providerConfig struct {
+	provider PriceProvider
+	timeout  time.Duration
+}
+
+// Aggregator queries several PriceProviders for the same symbol and
+// combines their results, isolating the rest of the widget from any single
+// provider being slow, rate-limited, or down.
+type Aggregator struct {
+	mu         sync.Mutex
+	providers  []providerConfig
+	breakers   map[string]*circuitBreaker
+	maxRetries int
+	strategy   AggregationStrategy
+}
+
+// NewAggregator builds an Aggregator over the given providers. Each provider
+// gets perProviderTimeout to answer before it's counted as failed for that
+// round; maxRetries controls how many times a single provider is retried
+// (with exponential backoff) within that timeout before giving up.
+func NewAggregator(providers []PriceProvider, perProviderTimeout time.Duration, maxRetries int, strategy AggregationStrategy) *Aggregator {
+	configs := make([]providerConfig, 0, len(providers))
+	breakers := make(map[string]*circuitBreaker, len(providers))
+	for _, p := range providers {
+		configs = append(configs, providerConfig{provider: p, timeout: perProviderTimeout})
+		breakers[p.Name()] = newCircuitBreaker(3, 30*time.Second)
+	}
+	return &Aggregator{
+		providers:  configs,
+		breakers:   breakers,
+		maxRetries: maxRetries,
+		strategy:   strategy,
+	}
+}
+
+// quoteResult is one provider's outcome for a single Fetch call.
+type quoteResult struct {
+	provider string
+	price    decimal.Decimal
+	err      error
+}
+
+// Fetch queries every configured provider for symbol/quote in parallel and
+// combines the successful results according to the aggregator's strategy.
+// Providers whose circuit breaker is open are skipped entirely. Fetch
+// returns an error only when every provider failed or was skipped.
+func (a *Aggregator) Fetch(ctx context.Context, symbol, quote string) (decimal.Decimal, error) {
+	a.mu.Lock()
+	providers := append([]providerConfig(nil), a.providers...)
+	a.mu.Unlock()
+
+	results := make(chan quoteResult, len(providers))
+	var wg sync.WaitGroup
+	for _, pc := range providers {
+		breaker := a.breakers[pc.provider.Name()]
+		if !breaker.Allow() {
+			continue
+		}
+		wg.Add(1)
+		go func(pc providerConfig, breaker *circuitBreaker) {
+			defer wg.Done()
+			price, err := a.fetchWithRetry(ctx, pc, symbol, quote)
+			if err != nil {
+				breaker.RecordFailure()
+			} else {
+				breaker.RecordSuccess()
+			}
+			results <- quoteResult{provider: pc.provider.Name(), price: price, err: err}
+		}(pc, breaker)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var prices []decimal.Decimal
+	var errs []error
+	for r := range results {
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.provider, r.err))
+			continue
+		}
+		prices = append(prices, r.price)
+		if a.strategy == StrategyFailover {
+			// First healthy provider wins; let the remaining goroutines
+			// finish in the background so their circuit breaker state is
+			// still recorded, but don't wait on them.
+			return r.price, nil
+		}
+	}
+
+	if len(prices) == 0 {
+		return decimal.Zero, fmt.Errorf("all providers failed for %s/%s: %w", symbol, quote, combineErrors(errs))
+	}
+	return median(prices), nil
+}
+
+// fetchWithRetry calls provider.FetchPrice, retrying up to maxRetries times
+// with exponential backoff and jitter while pc.timeout hasn't elapsed.
+func (a *Aggregator) fetchWithRetry(ctx context.Context, pc providerConfig, symbol, quote string) (decimal.Decimal, error) {
+	ctx, cancel := context.WithTimeout(ctx, pc.timeout)
+	defer cancel()
+
+	var lastErr error
+	backoff := 100 * time.Millisecond
+	for attempt := 0; attempt <= a.maxRetries; attempt++ {
+		price, err := pc.provider.FetchPrice(ctx, symbol, quote)
+		if err == nil {
+			return price, nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return decimal.Zero, lastErr
+		case <-time.After(jitter(backoff)):
+		}
+		backoff *= 2
+	}
+	return decimal.Zero, lastErr
+}
+
+// median returns the middle value of prices, averaging the two middle
+// values when there's an even count. prices is sorted in place.
+func median(prices []decimal.Decimal) decimal.Decimal {
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LessThan(prices[j]) })
+	n := len(prices)
+	if n%2 == 1 {
+		return prices[n/2]
+	}
+	return prices[n/2-1].Add(prices[n/2]).Div(decimal.NewFromInt(2))
+}
+
+func combineErrors(errs []error) error {
+	if len(errs) == 0 {
+		return fmt.Errorf("no providers configured")
+	}
+	msg := errs[0].Error()
+	for _, e := range errs[1:] {
+		msg += "; " + e.Error()
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// circuitBreaker is a minimal in-memory breaker: after threshold consecutive
+// failures it opens for cooldown, rejecting calls until the cooldown
+// elapses, at which point it allows one trial call (half-open) before
+// fully closing again on success.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	open      bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Allow reports whether a call should be attempted right now.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if time.Since(b.openedAt) >= b.cooldown {
+		// Half-open: let one call through to test recovery, restarting the
+		// cooldown so further calls stay blocked until it reports back.
+		b.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// RecordSuccess closes the breaker and resets the failure count.
+func (b *circuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.open = false
+}
+
+// RecordFailure counts a failure and opens the breaker once threshold is hit.
+func (b *circuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = time.Now()
+	}
+}
+
+// jitter returns d scaled by a random factor in [0.5, 1.5) to avoid thundering
+// herds of retrying providers all backing off in lockstep.
+func jitter(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (0.5 + rand.Float64()))
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/widgets"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/config"
+)
+
+// alertOperators lists the operators offered in the Add/Edit alert dialog,
+// in the order they appear in the QComboBox.
+var alertOperators = []string{string(config.OperatorGTE), string(config.OperatorLTE), string(config.OperatorCrosses)}
+
+// showAddAlertDialog opens a QDialog to create a new alert for symbol. It's
+// a no-op if symbol is empty, which happens when the context menu was
+// opened somewhere other than a tracked row.
+func (w *BinanceWidget) showAddAlertDialog(symbol string) {
+	if symbol == "" {
+		return
+	}
+	w.showAlertDialog(fmt.Sprintf("Add alert for %s", symbol), config.Alert{
+		Symbol:   symbol,
+		Operator: config.OperatorGTE,
+		Enabled:  true,
+	}, func(edited config.Alert) {
+		edited.ID = config.GenerateAlertID()
+		edited.CreatedAt = time.Now()
+		if err := w.alerts.Add(edited); err != nil {
+			fmt.Println("Error adding alert:", err)
+		}
+	})
+}
+
+// showEditAlertDialog opens a QDialog pre-filled with an existing alert's
+// fields and replaces it with the edited version on accept.
+func (w *BinanceWidget) showEditAlertDialog(alert config.Alert) {
+	w.showAlertDialog(fmt.Sprintf("Edit alert for %s", alert.Symbol), alert, func(edited config.Alert) {
+		edited.ID = alert.ID
+		edited.CreatedAt = alert.CreatedAt
+		if err := w.alerts.Remove(alert.ID); err != nil {
+			fmt.Println("Error updating alert:", err)
+			return
+		}
+		if err := w.alerts.Add(edited); err != nil {
+			fmt.Println("Error updating alert:", err)
+		}
+	})
+}
+
+// showAlertDialog builds the shared Add/Edit alert form, pre-filled from
+// initial, and calls onSave with the edited alert if the user accepts.
+// ID and CreatedAt are left to the caller, since Add and Edit assign them
+// differently.
+func (w *BinanceWidget) showAlertDialog(title string, initial config.Alert, onSave func(config.Alert)) {
+	dialog := widgets.NewQDialog(w, core.Qt__Dialog)
+	dialog.SetWindowTitle(title)
+
+	symbolEdit := widgets.NewQLineEdit2(initial.Symbol, nil)
+	priceEdit := widgets.NewQLineEdit2(initial.TargetPrice.String(), nil)
+	operatorBox := widgets.NewQComboBox(nil)
+	operatorBox.AddItems(alertOperators)
+	operatorBox.SetCurrentText(string(initial.Operator))
+	enabledBox := widgets.NewQCheckBox2("Enabled", nil)
+	enabledBox.SetChecked(initial.ID == "" || initial.Enabled)
+
+	form := widgets.NewQFormLayout(nil)
+	form.AddRow3("Symbol", symbolEdit)
+	form.AddRow3("Target price", priceEdit)
+	form.AddRow3("Operator", operatorBox)
+	form.AddRow5(enabledBox)
+
+	buttons := widgets.NewQDialogButtonBox2(widgets.QDialogButtonBox__Ok|widgets.QDialogButtonBox__Cancel, core.Qt__Horizontal, nil)
+	buttons.ConnectAccepted(dialog.Accept)
+	buttons.ConnectRejected(dialog.Reject)
+	form.AddRow5(buttons)
+	dialog.SetLayout(form)
+
+	if dialog.Exec() != int(widgets.QDialog__Accepted) {
+		return
+	}
+
+	target, err := decimal.NewFromString(priceEdit.Text())
+	if err != nil {
+		fmt.Println("Invalid target price, alert not saved:", err)
+		return
+	}
+	onSave(config.Alert{
+		Symbol:      symbolEdit.Text(),
+		TargetPrice: target,
+		Operator:    config.AlertOperator(operatorBox.CurrentText()),
+		Enabled:     enabledBox.IsChecked(),
+	})
+}
+
+// showManageAlertsDialog lists every configured alert across all symbols
+// and lets the user edit, remove, or re-arm them.
+func (w *BinanceWidget) showManageAlertsDialog() {
+	dialog := widgets.NewQDialog(w, core.Qt__Dialog)
+	dialog.SetWindowTitle("Manage alerts")
+
+	table := widgets.NewQTableWidget(nil)
+	table.SetColumnCount(4)
+	table.SetHorizontalHeaderLabels([]string{"Symbol", "Condition", "Status", ""})
+
+	var refresh func()
+	addRow := func(row int, alert config.Alert) {
+		table.SetItem(row, 0, widgets.NewQTableWidgetItem2(alert.Symbol, 0))
+		table.SetItem(row, 1, widgets.NewQTableWidgetItem2(fmt.Sprintf("%s %s", alert.Operator, alert.TargetPrice.String()), 0))
+
+		status := "armed"
+		switch {
+		case alert.Triggered:
+			status = "triggered"
+		case !alert.Enabled:
+			status = "disabled"
+		}
+		table.SetItem(row, 2, widgets.NewQTableWidgetItem2(status, 0))
+
+		actions := widgets.NewQWidget(nil, 0)
+		actionsLayout := widgets.NewQHBoxLayout()
+		editButton := widgets.NewQPushButton2("Edit", nil)
+		rearmButton := widgets.NewQPushButton2("Re-arm", nil)
+		rearmButton.SetEnabled(alert.Triggered)
+		removeButton := widgets.NewQPushButton2("Remove", nil)
+		actionsLayout.AddWidget(editButton, 0, 0)
+		actionsLayout.AddWidget(rearmButton, 0, 0)
+		actionsLayout.AddWidget(removeButton, 0, 0)
+		actions.SetLayout(actionsLayout)
+		table.SetCellWidget(row, 3, actions)
+
+		editButton.ConnectClicked(func(checked bool) {
+			dialog.Close()
+			w.showEditAlertDialog(alert)
+		})
+		rearmButton.ConnectClicked(func(checked bool) {
+			if err := w.alerts.Rearm(alert.ID); err != nil {
+				fmt.Println("Error re-arming alert:", err)
+			}
+			refresh()
+		})
+		removeButton.ConnectClicked(func(checked bool) {
+			if err := w.alerts.Remove(alert.ID); err != nil {
+				fmt.Println("Error removing alert:", err)
+			}
+			refresh()
+		})
+	}
+
+	refresh = func() {
+		alerts := w.alerts.List()
+		table.SetRowCount(len(alerts))
+		for i, alert := range alerts {
+			addRow(i, alert)
+		}
+	}
+	refresh()
+
+	layout := widgets.NewQVBoxLayout()
+	layout.AddWidget(table, 0, 0)
+	closeButton := widgets.NewQPushButton2("Close", nil)
+	closeButton.ConnectClicked(func(checked bool) { dialog.Close() })
+	layout.AddWidget(closeButton, 0, 0)
+	dialog.SetLayout(layout)
+
+	dialog.Exec()
+}
@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"github.com/therecipe/qt/widgets"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/config"
+)
+
+// evaluateAlert reports whether alert should fire given the symbol's
+// previous and current price. It never fires a disabled or already
+// triggered alert (one-shot semantics; callers re-arm by resetting
+// Triggered). validCurrent being false models a bad/NaN reading from a
+// provider: such ticks are ignored rather than (incorrectly) triggering or
+// clearing a pending cross.
+func evaluateAlert(alert config.Alert, prev decimal.Decimal, havePrev bool, current decimal.Decimal, validCurrent bool) bool {
+	if !alert.Enabled || alert.Triggered || !validCurrent {
+		return false
+	}
+
+	switch alert.Operator {
+	case config.OperatorGTE:
+		return current.GreaterThanOrEqual(alert.TargetPrice)
+	case config.OperatorLTE:
+		return current.LessThanOrEqual(alert.TargetPrice)
+	case config.OperatorCrosses:
+		if !havePrev {
+			return false
+		}
+		prevSide := prev.Cmp(alert.TargetPrice)
+		currSide := current.Cmp(alert.TargetPrice)
+		if prevSide == 0 {
+			// Already sitting on the target last tick; only a genuine
+			// move to the other side counts as a new cross.
+			return false
+		}
+		if currSide == 0 {
+			return true
+		}
+		return (prevSide < 0) != (currSide < 0)
+	default:
+		return false
+	}
+}
+
+// AlertManager owns the widget's alert collection: it evaluates alerts on
+// every price update, persists them to ~/.pricetrack.json, and fires
+// desktop notifications when one triggers.
+type AlertManager struct {
+	mu        sync.Mutex
+	alerts    []config.Alert
+	lastPrice map[string]decimal.Decimal
+
+	tray *widgets.QSystemTrayIcon
+}
+
+// NewAlertManager loads any alerts already persisted in the config file and
+// returns a ready-to-use AlertManager. tray may be nil, in which case
+// triggered alerts are logged but no desktop notification is shown.
+func NewAlertManager(tray *widgets.QSystemTrayIcon) *AlertManager {
+	alerts, err := config.LoadAlerts()
+	if err != nil {
+		fmt.Println("Error loading alerts:", err)
+	}
+	return &AlertManager{
+		alerts:    alerts,
+		lastPrice: make(map[string]decimal.Decimal),
+		tray:      tray,
+	}
+}
+
+// CheckPrice evaluates every alert on symbol against its previous and
+// current price, firing notifications for any that trigger.
+func (m *AlertManager) CheckPrice(symbol string, price decimal.Decimal) {
+	m.mu.Lock()
+	prev, havePrev := m.lastPrice[symbol]
+	m.lastPrice[symbol] = price
+
+	var triggered []config.Alert
+	for i := range m.alerts {
+		alert := &m.alerts[i]
+		if alert.Symbol != symbol {
+			continue
+		}
+		if evaluateAlert(*alert, prev, havePrev, price, true) {
+			alert.Triggered = true
+			triggered = append(triggered, *alert)
+		}
+	}
+	dirty := len(triggered) > 0
+	alertsCopy := append([]config.Alert(nil), m.alerts...)
+	m.mu.Unlock()
+
+	if dirty {
+		if err := config.SaveAlerts(alertsCopy); err != nil {
+			fmt.Println("Error saving alerts:", err)
+		}
+	}
+	for _, alert := range triggered {
+		m.notify(alert, price)
+	}
+}
+
+// Add appends a new alert and persists the updated collection.
+func (m *AlertManager) Add(alert config.Alert) error {
+	m.mu.Lock()
+	m.alerts = append(m.alerts, alert)
+	alertsCopy := append([]config.Alert(nil), m.alerts...)
+	m.mu.Unlock()
+	return config.SaveAlerts(alertsCopy)
+}
+
+// Remove deletes the alert with the given ID and persists the change.
+func (m *AlertManager) Remove(id string) error {
+	m.mu.Lock()
+	filtered := m.alerts[:0]
+	for _, a := range m.alerts {
+		if a.ID != id {
+			filtered = append(filtered, a)
+		}
+	}
+	m.alerts = filtered
+	alertsCopy := append([]config.Alert(nil), m.alerts...)
+	m.mu.Unlock()
+	return config.SaveAlerts(alertsCopy)
+}
+
+// Rearm clears Triggered on the alert with the given ID so it can fire
+// again, and persists the change.
+func (m *AlertManager) Rearm(id string) error {
+	m.mu.Lock()
+	for i := range m.alerts {
+		if m.alerts[i].ID == id {
+			m.alerts[i].Triggered = false
+		}
+	}
+	alertsCopy := append([]config.Alert(nil), m.alerts...)
+	m.mu.Unlock()
+	return config.SaveAlerts(alertsCopy)
+}
+
+// Reload replaces the in-memory alert list with alerts, e.g. once the
+// ConfigWatcher detects another process changed the shared config file.
+// It doesn't persist -- alerts was just read from that same file, so
+// there's nothing new to save.
+func (m *AlertManager) Reload(alerts []config.Alert) {
+	m.mu.Lock()
+	m.alerts = append([]config.Alert(nil), alerts...)
+	m.mu.Unlock()
+}
+
+// List returns a snapshot of the current alerts.
+func (m *AlertManager) List() []config.Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]config.Alert(nil), m.alerts...)
+}
+
+// ForSymbol returns the alerts configured for a single symbol.
+func (m *AlertManager) ForSymbol(symbol string) []config.Alert {
+	var out []config.Alert
+	for _, a := range m.List() {
+		if a.Symbol == symbol {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// notify shows a desktop notification for a triggered alert via the
+// widget's system tray icon, plus a bell sound. It must be called on or
+// marshaled to the GUI thread by the caller.
+func (m *AlertManager) notify(alert config.Alert, price decimal.Decimal) {
+	title := fmt.Sprintf("%s price alert", alert.Symbol)
+	body := fmt.Sprintf("%s %s %s reached (now %s)", alert.Symbol, alert.Operator, alert.TargetPrice.String(), price.String())
+	if m.tray != nil {
+		m.tray.ShowMessage(title, body, widgets.NewQIcon(), widgets.QSystemTrayIcon__Information, 5000)
+	} else {
+		fmt.Printf("%s: %s\n", title, body)
+	}
+	widgets.QApplication_Beep()
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/config"
+)
+
+func mustDecimal(t *testing.T, s string) decimal.Decimal {
+	t.Helper()
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return d
+}
+
+func TestEvaluateAlertGTE(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorGTE, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	if evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "99"), true) {
+		t.Error("should not fire below target")
+	}
+	if !evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "100"), true) {
+		t.Error("should fire at target")
+	}
+	if !evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "101"), true) {
+		t.Error("should fire above target")
+	}
+}
+
+func TestEvaluateAlertLTE(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorLTE, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	if evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "101"), true) {
+		t.Error("should not fire above target")
+	}
+	if !evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "100"), true) {
+		t.Error("should fire at target")
+	}
+	if !evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "99"), true) {
+		t.Error("should fire below target")
+	}
+}
+
+func TestEvaluateAlertCrossesFromBelow(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorCrosses, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	if !evaluateAlert(alert, mustDecimal(t, "99"), true, mustDecimal(t, "101"), true) {
+		t.Error("should fire when crossing from below to above")
+	}
+}
+
+func TestEvaluateAlertCrossesFromAbove(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorCrosses, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	if !evaluateAlert(alert, mustDecimal(t, "101"), true, mustDecimal(t, "99"), true) {
+		t.Error("should fire when crossing from above to below")
+	}
+}
+
+func TestEvaluateAlertCrossesNoPriorTick(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorCrosses, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	if evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "101"), true) {
+		t.Error("should not fire a cross without a previous price to compare against")
+	}
+}
+
+func TestEvaluateAlertCrossesRepeatedSamePrice(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorCrosses, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	price := mustDecimal(t, "105")
+	if evaluateAlert(alert, price, true, price, true) {
+		t.Error("repeated identical ticks should not be seen as a cross")
+	}
+}
+
+func TestEvaluateAlertCrossesSittingOnTarget(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorCrosses, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+	target := mustDecimal(t, "100")
+
+	if evaluateAlert(alert, target, true, target, true) {
+		t.Error("sitting exactly on the target across ticks is not a new cross")
+	}
+	if !evaluateAlert(alert, target, true, mustDecimal(t, "101"), true) {
+		t.Error("moving off the target to either side should count as reaching it")
+	}
+}
+
+func TestEvaluateAlertIgnoresInvalidCurrentTick(t *testing.T) {
+	alert := config.Alert{Operator: config.OperatorGTE, TargetPrice: mustDecimal(t, "100"), Enabled: true}
+
+	if evaluateAlert(alert, decimal.Zero, false, mustDecimal(t, "200"), false) {
+		t.Error("an invalid/NaN-like current reading must never trigger an alert")
+	}
+}
+
+func TestEvaluateAlertDisabledOrTriggeredNeverFires(t *testing.T) {
+	disabled := config.Alert{Operator: config.OperatorGTE, TargetPrice: mustDecimal(t, "100"), Enabled: false}
+	if evaluateAlert(disabled, decimal.Zero, false, mustDecimal(t, "200"), true) {
+		t.Error("a disabled alert must not fire")
+	}
+
+	triggered := config.Alert{Operator: config.OperatorGTE, TargetPrice: mustDecimal(t, "100"), Enabled: true, Triggered: true}
+	if evaluateAlert(triggered, decimal.Zero, false, mustDecimal(t, "200"), true) {
+		t.Error("an already-triggered one-shot alert must not re-fire")
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/therecipe/qt/core"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/config"
+)
+
+// ConfigWatcher watches the shared config file for changes written by
+// another process -- most commonly a second widget instance adding or
+// removing a symbol or alert -- and reconciles the running widget with the
+// new contents without requiring a restart.
+type ConfigWatcher struct {
+	widget  *BinanceWidget
+	watcher *fsnotify.Watcher
+}
+
+// NewConfigWatcher creates a ConfigWatcher for widget's shared config
+// file. It watches the file's parent directory rather than the file
+// itself, since writeConfigFile replaces the file by renaming a temp file
+// over it rather than writing in place, and a rename-based replace doesn't
+// fire further events against the original (now-deleted) inode.
+func NewConfigWatcher(widget *BinanceWidget) (*ConfigWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create config watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(config.FilePath())); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("watch config directory: %w", err)
+	}
+	return &ConfigWatcher{widget: widget, watcher: watcher}, nil
+}
+
+// Start runs the watch loop in a goroutine until Stop is called.
+func (cw *ConfigWatcher) Start() {
+	go cw.run()
+}
+
+// Stop closes the underlying filesystem watcher, ending the watch loop.
+func (cw *ConfigWatcher) Stop() {
+	cw.watcher.Close()
+}
+
+// run is the watch loop. It ignores every directory event except a
+// create, write, or rename naming the config file itself, e.g. skipping
+// writeConfigFile's temp files and withConfigLock's lock file.
+func (cw *ConfigWatcher) run() {
+	path := filepath.Clean(config.FilePath())
+	for {
+		select {
+		case event, ok := <-cw.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != path {
+				continue
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) != 0 {
+				cw.reload()
+			}
+		case err, ok := <-cw.watcher.Errors:
+			if !ok {
+				return
+			}
+			fmt.Println("config watcher error:", err)
+		}
+	}
+}
+
+// reload re-reads the config file and marshals the reconciliation onto the
+// GUI thread. A transient read error (e.g. racing another process mid
+// temp-file-then-rename) is logged and skipped rather than applied, since
+// the rename that follows will fire its own event and retry.
+func (cw *ConfigWatcher) reload() {
+	cf, err := config.ReadConfigFile(config.FilePath())
+	if err != nil {
+		fmt.Println("config watcher: reload failed:", err)
+		return
+	}
+	core.QMetaObject_InvokeMethod4(cw.widget, func() {
+		cw.widget.applyConfig(cf)
+	}, core.Qt__QueuedConnection)
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/widgets"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/config"
+	"github.com/zewebdev1337/price-tracker-widget/internal/portfolio"
+)
+
+const holdingSpinBoxDecimals = 8
+
+// showEditHoldingsDialog opens a QDialog to record a buy or sell of symbol
+// at a given price. The trade is folded into the existing holding via
+// portfolio.ApplyTrade's average-cost accounting, rather than asking the
+// user to hand-compute a new running Amount/CostBasis total themselves.
+func (w *BinanceWidget) showEditHoldingsDialog(symbol string) {
+	if symbol == "" {
+		return
+	}
+
+	initial, _ := w.portfolio.Get(symbol)
+
+	dialog := widgets.NewQDialog(w, core.Qt__Dialog)
+	dialog.SetWindowTitle(fmt.Sprintf("Record a trade for %s", symbol))
+
+	currentLabel := widgets.NewQLabel2(fmt.Sprintf("Currently holding %s %s (cost basis %s %s)",
+		initial.Amount.String(), symbol, initial.CostBasis.String(), initial.CostCurrency(quoteCurrency)), nil, 0)
+
+	tradeAmountBox := widgets.NewQDoubleSpinBox(nil)
+	tradeAmountBox.SetDecimals(holdingSpinBoxDecimals)
+	tradeAmountBox.SetRange(-1e15, 1e15)
+
+	tradePriceBox := widgets.NewQDoubleSpinBox(nil)
+	tradePriceBox.SetDecimals(holdingSpinBoxDecimals)
+	tradePriceBox.SetRange(0, 1e15)
+	if price, ok := w.lastPrices[symbol]; ok {
+		tradePriceBox.SetValue(decimalToFloat(price))
+	}
+
+	currencyEdit := widgets.NewQLineEdit2(initial.QuoteCurrency, nil)
+	currencyEdit.SetPlaceholderText(fmt.Sprintf("(same as %s)", quoteCurrency))
+
+	form := widgets.NewQFormLayout(nil)
+	form.AddRow5(currentLabel)
+	form.AddRow3("Trade amount (+buy / -sell)", tradeAmountBox)
+	form.AddRow3("Trade price", tradePriceBox)
+	form.AddRow3("Cost currency", currencyEdit)
+
+	buttons := widgets.NewQDialogButtonBox2(widgets.QDialogButtonBox__Ok|widgets.QDialogButtonBox__Cancel, core.Qt__Horizontal, nil)
+	buttons.ConnectAccepted(dialog.Accept)
+	buttons.ConnectRejected(dialog.Reject)
+	form.AddRow5(buttons)
+	dialog.SetLayout(form)
+
+	if dialog.Exec() != int(widgets.QDialog__Accepted) {
+		return
+	}
+
+	if currency := currencyEdit.Text(); currency != "" {
+		initial.QuoteCurrency = currency
+	}
+
+	tradeAmount := decimal.NewFromFloat(tradeAmountBox.Value())
+	updated := portfolio.ApplyTrade(initial, tradeAmount, decimal.NewFromFloat(tradePriceBox.Value()))
+	if updated.Amount.IsZero() {
+		w.portfolio.Remove(symbol)
+	} else {
+		w.portfolio.Set(symbol, updated)
+		if currency := updated.QuoteCurrency; currency != "" && currency != quoteCurrency {
+			go w.refreshFXRate(currency)
+		}
+	}
+
+	if err := config.SaveSymbols(w.currentConfigs()); err != nil {
+		fmt.Println("Error saving holdings:", err)
+	}
+	if price, ok := w.lastPrices[symbol]; ok {
+		w.refreshPortfolioLine(symbol, price)
+	} else {
+		w.refreshTotalLabel()
+	}
+}
+
+func decimalToFloat(d decimal.Decimal) float64 {
+	f, _ := d.Float64()
+	return f
+}
@@ -0,0 +1,505 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+// PriceProvider is implemented by anything that can quote a symbol against
+// a quote currency. Providers are intentionally small and stateless so they
+// can be constructed once and shared across goroutines.
+type PriceProvider interface {
+	// Name returns the provider's short identifier, used in config files and
+	// error messages (e.g. "binance", "coinbase").
+	Name() string
+
+	// FetchPrice returns the last traded price of symbol/quote, e.g. BTC/USDT.
+	FetchPrice(ctx context.Context, symbol, quote string) (decimal.Decimal, error)
+
+	// SupportedSymbols lists the base symbols this provider can quote. The
+	// widget calls it once at startup (see validateProviderSupport) to warn
+	// about a row explicitly configured for a provider that can't serve it.
+	// It isn't consulted on every fetch -- a provider that can't serve a
+	// given row at request time is instead skipped there by Aggregator's
+	// own per-provider failover.
+	SupportedSymbols(ctx context.Context) ([]string, error)
+}
+
+// httpClientTimeout bounds a single round trip so a slow provider can't hang
+// the whole aggregator; the aggregator additionally enforces its own
+// per-provider timeout via context.
+const httpClientTimeout = 10 * time.Second
+
+var sharedHTTPClient = &http.Client{Timeout: httpClientTimeout}
+
+func doJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := sharedHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 429 {
+		return errRateLimited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// errRateLimited is returned by a provider when it has been throttled. The
+// aggregator treats it the same as any other failure for failover purposes,
+// but callers can check for it with errors.Is to back off harder.
+var errRateLimited = fmt.Errorf("rate limited")
+
+// BinanceProvider talks to Binance's spot REST API and, via StreamTicks,
+// its combined trade-stream websocket endpoint.
+type BinanceProvider struct {
+	baseURL   string
+	streamURL string
+}
+
+// NewBinanceProvider creates a BinanceProvider using Binance's public API.
+func NewBinanceProvider() *BinanceProvider {
+	return &BinanceProvider{
+		baseURL:   "https://api.binance.com",
+		streamURL: "wss://stream.binance.com:9443/stream",
+	}
+}
+
+func (p *BinanceProvider) Name() string { return "binance" }
+
+func (p *BinanceProvider) FetchPrice(ctx context.Context, symbol, quote string) (decimal.Decimal, error) {
+	var data struct {
+		Price string `json:"price"`
+	}
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s%s", p.baseURL, strings.ToUpper(symbol), strings.ToUpper(quote))
+	if err := doJSON(ctx, url, &data); err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromString(data.Price)
+}
+
+func (p *BinanceProvider) SupportedSymbols(ctx context.Context) ([]string, error) {
+	var data struct {
+		Symbols []struct {
+			BaseAsset string `json:"baseAsset"`
+		} `json:"symbols"`
+	}
+	if err := doJSON(ctx, p.baseURL+"/api/v3/exchangeInfo", &data); err != nil {
+		return nil, err
+	}
+	symbols := make([]string, 0, len(data.Symbols))
+	for _, s := range data.Symbols {
+		symbols = append(symbols, s.BaseAsset)
+	}
+	return symbols, nil
+}
+
+// Klines backfills a sparkline from Binance's public klines endpoint,
+// using each candle's close price and close time as a sample.
+func (p *BinanceProvider) Klines(ctx context.Context, symbol, quote string, interval time.Duration, limit int) ([]priceSample, error) {
+	binanceInterval, err := binanceKlineInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw [][]interface{}
+	url := fmt.Sprintf("%s/api/v3/klines?symbol=%s%s&interval=%s&limit=%d",
+		p.baseURL, strings.ToUpper(symbol), strings.ToUpper(quote), binanceInterval, limit)
+	if err := doJSON(ctx, url, &raw); err != nil {
+		return nil, err
+	}
+
+	samples := make([]priceSample, 0, len(raw))
+	for _, candle := range raw {
+		if len(candle) < 7 {
+			continue
+		}
+		closeStr, ok := candle[4].(string)
+		if !ok {
+			continue
+		}
+		closeTimeMs, ok := candle[6].(float64)
+		if !ok {
+			continue
+		}
+		price, err := decimal.NewFromString(closeStr)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, priceSample{At: time.UnixMilli(int64(closeTimeMs)), Price: price})
+	}
+	return samples, nil
+}
+
+// binanceKlineInterval maps a lookback granularity to the interval string
+// Binance's klines endpoint expects. Only the granularities the widget
+// actually requests are supported.
+func binanceKlineInterval(interval time.Duration) (string, error) {
+	switch interval {
+	case time.Minute:
+		return "1m", nil
+	case time.Hour:
+		return "1h", nil
+	default:
+		return "", fmt.Errorf("unsupported kline interval %s", interval)
+	}
+}
+
+// StreamTicks subscribes to Binance's combined `<symbol><quote>@trade`
+// streams for every symbol and pushes a TickUpdate per trade.
+func (p *BinanceProvider) StreamTicks(ctx context.Context, symbols []string, quote string, updates chan<- TickUpdate) error {
+	streamNames := make([]string, 0, len(symbols))
+	byStreamName := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		name := binanceStreamSymbol(symbol, quote) + "@trade"
+		streamNames = append(streamNames, name)
+		byStreamName[name] = symbol
+	}
+	url := fmt.Sprintf("%s?streams=%s", p.streamURL, strings.Join(streamNames, "/"))
+
+	return streamReconnectLoop(ctx, p.Name(), func(ctx context.Context) (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+		return conn, err
+	}, func(ctx context.Context, conn *websocket.Conn) error {
+		failed := heartbeat(ctx, conn, 30*time.Second)
+		for {
+			select {
+			case err := <-failed:
+				return err
+			default:
+			}
+
+			var frame struct {
+				Stream string `json:"stream"`
+				Data   struct {
+					Price string `json:"p"`
+				} `json:"data"`
+			}
+			if err := conn.ReadJSON(&frame); err != nil {
+				return err
+			}
+			symbol, ok := byStreamName[frame.Stream]
+			if !ok {
+				continue
+			}
+			price, err := decimal.NewFromString(frame.Data.Price)
+			if err != nil {
+				continue
+			}
+			updates <- TickUpdate{Symbol: symbol, Price: price}
+		}
+	})
+}
+
+// CoinbaseProvider talks to Coinbase's Exchange REST API and its
+// `ticker` websocket channel.
+type CoinbaseProvider struct {
+	baseURL   string
+	streamURL string
+}
+
+// NewCoinbaseProvider creates a CoinbaseProvider using Coinbase's public API.
+func NewCoinbaseProvider() *CoinbaseProvider {
+	return &CoinbaseProvider{
+		baseURL:   "https://api.exchange.coinbase.com",
+		streamURL: "wss://ws-feed.exchange.coinbase.com",
+	}
+}
+
+func (p *CoinbaseProvider) Name() string { return "coinbase" }
+
+func (p *CoinbaseProvider) FetchPrice(ctx context.Context, symbol, quote string) (decimal.Decimal, error) {
+	var data struct {
+		Price string `json:"price"`
+	}
+	pair := fmt.Sprintf("%s-%s", strings.ToUpper(symbol), strings.ToUpper(quote))
+	url := fmt.Sprintf("%s/products/%s/ticker", p.baseURL, pair)
+	if err := doJSON(ctx, url, &data); err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromString(data.Price)
+}
+
+func (p *CoinbaseProvider) SupportedSymbols(ctx context.Context) ([]string, error) {
+	var data []struct {
+		BaseCurrency string `json:"base_currency"`
+	}
+	if err := doJSON(ctx, p.baseURL+"/products", &data); err != nil {
+		return nil, err
+	}
+	symbols := make([]string, 0, len(data))
+	for _, s := range data {
+		symbols = append(symbols, s.BaseCurrency)
+	}
+	return symbols, nil
+}
+
+// StreamTicks subscribes to Coinbase's `ticker` channel for every symbol
+// and pushes a TickUpdate on each update message.
+func (p *CoinbaseProvider) StreamTicks(ctx context.Context, symbols []string, quote string, updates chan<- TickUpdate) error {
+	productIDs := make([]string, 0, len(symbols))
+	byProductID := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		id := fmt.Sprintf("%s-%s", strings.ToUpper(symbol), strings.ToUpper(quote))
+		productIDs = append(productIDs, id)
+		byProductID[id] = symbol
+	}
+
+	return streamReconnectLoop(ctx, p.Name(), func(ctx context.Context) (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, p.streamURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		sub := map[string]interface{}{
+			"type":        "subscribe",
+			"product_ids": productIDs,
+			"channels":    []string{"ticker"},
+		}
+		if err := conn.WriteJSON(sub); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("subscribe: %w", err)
+		}
+		return conn, nil
+	}, func(ctx context.Context, conn *websocket.Conn) error {
+		failed := heartbeat(ctx, conn, 30*time.Second)
+		for {
+			select {
+			case err := <-failed:
+				return err
+			default:
+			}
+
+			var msg struct {
+				Type      string `json:"type"`
+				ProductID string `json:"product_id"`
+				Price     string `json:"price"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return err
+			}
+			if msg.Type != "ticker" {
+				continue
+			}
+			symbol, ok := byProductID[msg.ProductID]
+			if !ok {
+				continue
+			}
+			price, err := decimal.NewFromString(msg.Price)
+			if err != nil {
+				continue
+			}
+			updates <- TickUpdate{Symbol: symbol, Price: price}
+		}
+	})
+}
+
+// KrakenProvider talks to Kraken's public REST API and its `ticker`
+// websocket channel.
+type KrakenProvider struct {
+	baseURL   string
+	streamURL string
+}
+
+// NewKrakenProvider creates a KrakenProvider using Kraken's public API.
+func NewKrakenProvider() *KrakenProvider {
+	return &KrakenProvider{
+		baseURL:   "https://api.kraken.com",
+		streamURL: "wss://ws.kraken.com",
+	}
+}
+
+func (p *KrakenProvider) Name() string { return "kraken" }
+
+func (p *KrakenProvider) FetchPrice(ctx context.Context, symbol, quote string) (decimal.Decimal, error) {
+	pair := krakenSymbol(symbol) + strings.ToUpper(quote)
+	var data struct {
+		Result map[string]struct {
+			C []string `json:"c"`
+		} `json:"result"`
+		Error []string `json:"error"`
+	}
+	url := fmt.Sprintf("%s/0/public/Ticker?pair=%s", p.baseURL, pair)
+	if err := doJSON(ctx, url, &data); err != nil {
+		return decimal.Zero, err
+	}
+	if len(data.Error) > 0 {
+		return decimal.Zero, fmt.Errorf("kraken: %s", strings.Join(data.Error, "; "))
+	}
+	for _, ticker := range data.Result {
+		if len(ticker.C) == 0 {
+			continue
+		}
+		return decimal.NewFromString(ticker.C[0])
+	}
+	return decimal.Zero, fmt.Errorf("kraken: no ticker for %s", pair)
+}
+
+func (p *KrakenProvider) SupportedSymbols(ctx context.Context) ([]string, error) {
+	var data struct {
+		Result map[string]struct {
+			Base string `json:"base"`
+		} `json:"result"`
+	}
+	if err := doJSON(ctx, p.baseURL+"/0/public/AssetPairs", &data); err != nil {
+		return nil, err
+	}
+	symbols := make([]string, 0, len(data.Result))
+	for _, pair := range data.Result {
+		base := pair.Base
+		if strings.EqualFold(base, "XBT") {
+			base = "BTC"
+		}
+		symbols = append(symbols, base)
+	}
+	return symbols, nil
+}
+
+// krakenSymbol translates a symbol to Kraken's own asset code, which
+// differs only for BTC (Kraken calls it XBT).
+func krakenSymbol(symbol string) string {
+	if strings.EqualFold(symbol, "BTC") {
+		return "XBT"
+	}
+	return strings.ToUpper(symbol)
+}
+
+// krakenPair builds the `XBT/USD`-style pair Kraken's websocket API
+// expects; it normalizes the common BTC ticker to Kraken's own XBT symbol.
+func krakenPair(symbol, quote string) string {
+	return fmt.Sprintf("%s/%s", krakenSymbol(symbol), strings.ToUpper(quote))
+}
+
+// StreamTicks subscribes to Kraken's `ticker` channel for every symbol and
+// pushes a TickUpdate on each update message.
+func (p *KrakenProvider) StreamTicks(ctx context.Context, symbols []string, quote string, updates chan<- TickUpdate) error {
+	pairs := make([]string, 0, len(symbols))
+	byPair := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		pair := krakenPair(symbol, quote)
+		pairs = append(pairs, pair)
+		byPair[pair] = symbol
+	}
+
+	return streamReconnectLoop(ctx, p.Name(), func(ctx context.Context) (*websocket.Conn, error) {
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, p.streamURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		sub := map[string]interface{}{
+			"event": "subscribe",
+			"pair":  pairs,
+			"subscription": map[string]string{
+				"name": "ticker",
+			},
+		}
+		if err := conn.WriteJSON(sub); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("subscribe: %w", err)
+		}
+		return conn, nil
+	}, func(ctx context.Context, conn *websocket.Conn) error {
+		failed := heartbeat(ctx, conn, 30*time.Second)
+		for {
+			select {
+			case err := <-failed:
+				return err
+			default:
+			}
+
+			var raw []json.RawMessage
+			if err := conn.ReadJSON(&raw); err != nil {
+				// Kraken also sends non-array event/heartbeat objects on
+				// this connection; those fail to decode as an array and
+				// are simply not ticker updates, so skip them.
+				continue
+			}
+			if len(raw) < 4 {
+				continue
+			}
+			var ticker struct {
+				C []string `json:"c"`
+			}
+			var pair string
+			if err := json.Unmarshal(raw[1], &ticker); err != nil {
+				continue
+			}
+			if err := json.Unmarshal(raw[3], &pair); err != nil {
+				continue
+			}
+			symbol, ok := byPair[pair]
+			if !ok || len(ticker.C) == 0 {
+				continue
+			}
+			price, err := decimal.NewFromString(ticker.C[0])
+			if err != nil {
+				continue
+			}
+			updates <- TickUpdate{Symbol: symbol, Price: price}
+		}
+	})
+}
+
+// CoinGeckoProvider talks to CoinGecko's public REST API. It is the odd one
+// out among providers here: it's an index/aggregator itself rather than an
+// exchange, which makes it a useful fallback when CEXes disagree or are down.
+type CoinGeckoProvider struct {
+	baseURL string
+}
+
+// NewCoinGeckoProvider creates a CoinGeckoProvider using CoinGecko's public API.
+func NewCoinGeckoProvider() *CoinGeckoProvider {
+	return &CoinGeckoProvider{baseURL: "https://api.coingecko.com/api/v3"}
+}
+
+func (p *CoinGeckoProvider) Name() string { return "coingecko" }
+
+func (p *CoinGeckoProvider) FetchPrice(ctx context.Context, symbol, quote string) (decimal.Decimal, error) {
+	id, ok := coinGeckoIDs[strings.ToUpper(symbol)]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("coingecko: unknown symbol %s", symbol)
+	}
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s", p.baseURL, id, strings.ToLower(quote))
+	var data map[string]map[string]float64
+	if err := doJSON(ctx, url, &data); err != nil {
+		return decimal.Zero, err
+	}
+	price, ok := data[id][strings.ToLower(quote)]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("coingecko: no price for %s/%s", symbol, quote)
+	}
+	return decimal.NewFromFloat(price), nil
+}
+
+func (p *CoinGeckoProvider) SupportedSymbols(ctx context.Context) ([]string, error) {
+	symbols := make([]string, 0, len(coinGeckoIDs))
+	for symbol := range coinGeckoIDs {
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+// coinGeckoIDs maps the symbols this widget cares about to CoinGecko's
+// internal coin IDs, which rarely match the ticker (e.g. "bitcoin", not
+// "BTC"). Extend this map when adding support for a new symbol.
+var coinGeckoIDs = map[string]string{
+	"BTC": "bitcoin",
+	"ETH": "ethereum",
+	"SOL": "solana",
+}
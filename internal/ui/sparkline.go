@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/gui"
+	"github.com/therecipe/qt/widgets"
+)
+
+// defaultSparklineSamples is how many samples a "last N ticks" sparkline
+// keeps when no other window is selected.
+const defaultSparklineSamples = 60
+
+// HistoricalProvider is implemented by providers that can backfill past
+// prices from a klines/candles REST endpoint. It's optional like
+// Streamable: a row backfills its sparkline from the first of its
+// configured providers that implements it, and simply starts empty (filling
+// in from live ticks) for providers that don't.
+type HistoricalProvider interface {
+	// Klines returns up to limit historical samples for symbol/quote,
+	// oldest first, spaced roughly interval apart.
+	Klines(ctx context.Context, symbol, quote string, interval time.Duration, limit int) ([]priceSample, error)
+}
+
+// sparklineWindow selects which of a SparklineWidget's buffered samples
+// are visible: either the last N raw ticks, or every sample within a
+// fixed lookback duration.
+type sparklineWindow int
+
+const (
+	// windowTicks keeps the most recent maxSamples raw ticks, regardless
+	// of how much wall-clock time they span.
+	windowTicks sparklineWindow = iota
+	window1h
+	window24h
+)
+
+// String names the window for display in the right-click toggle menu.
+func (sw sparklineWindow) String() string {
+	switch sw {
+	case window1h:
+		return "1h"
+	case window24h:
+		return "24h"
+	default:
+		return "last N ticks"
+	}
+}
+
+// sparklineWindows lists every window in the order the context menu
+// offers them.
+var sparklineWindows = []sparklineWindow{windowTicks, window1h, window24h}
+
+// windowDuration maps a time-bounded window to its lookback duration. It's
+// meaningless for windowTicks, which is bounded by sample count instead.
+func windowDuration(window sparklineWindow) time.Duration {
+	switch window {
+	case window24h:
+		return 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+// priceSample is one (timestamp, price) point in a SparklineWidget's ring
+// buffer.
+type priceSample struct {
+	At    time.Time
+	Price decimal.Decimal
+}
+
+// SparklineWidget paints a small line chart of one symbol's recent prices,
+// colored green or red by its net change across the visible window. Its
+// ring buffer is fed by the widget's REST and streaming price updates, and
+// can be seeded up front from a provider's klines endpoint so the chart
+// isn't empty on startup.
+type SparklineWidget struct {
+	widgets.QWidget
+
+	mu         sync.Mutex
+	samples    []priceSample
+	window     sparklineWindow
+	maxSamples int
+}
+
+// NewSparklineWidget creates a sparkline with the default "last N ticks"
+// window and a ring buffer capped at maxSamples.
+func NewSparklineWidget(parent widgets.QWidget_ITF, maxSamples int) *SparklineWidget {
+	s := &SparklineWidget{
+		QWidget:    *widgets.NewQWidget(parent, 0),
+		window:     windowTicks,
+		maxSamples: maxSamples,
+	}
+	s.SetFixedHeight(20)
+	s.SetMinimumWidth(100)
+	s.ConnectPaintEvent(s.paintEvent)
+	return s
+}
+
+// Seed replaces the ring buffer with historical samples, e.g. backfilled
+// from a provider's klines endpoint at startup.
+func (s *SparklineWidget) Seed(samples []priceSample) {
+	s.mu.Lock()
+	s.samples = samples
+	s.mu.Unlock()
+	s.Repaint()
+}
+
+// Add appends a new live sample, evicting the oldest once the "last N
+// ticks" window is over capacity, and always dropping anything older than
+// the widest time-bounded window (24h) so the ring buffer stays bounded
+// even while a shorter window is selected.
+func (s *SparklineWidget) Add(sample priceSample) {
+	s.mu.Lock()
+	s.samples = append(s.samples, sample)
+	if s.window == windowTicks && len(s.samples) > s.maxSamples {
+		s.samples = s.samples[len(s.samples)-s.maxSamples:]
+	}
+	cutoff := sample.At.Add(-windowDuration(window24h))
+	trimmed := 0
+	for trimmed < len(s.samples) && s.samples[trimmed].At.Before(cutoff) {
+		trimmed++
+	}
+	s.samples = s.samples[trimmed:]
+	s.mu.Unlock()
+	s.Repaint()
+}
+
+// SetWindow switches which samples are visible and repaints.
+func (s *SparklineWidget) SetWindow(window sparklineWindow) {
+	s.mu.Lock()
+	s.window = window
+	s.mu.Unlock()
+	s.Repaint()
+}
+
+// Window reports the currently selected window.
+func (s *SparklineWidget) Window() sparklineWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.window
+}
+
+// visibleSamples returns, oldest first, the samples the current window
+// keeps.
+func (s *SparklineWidget) visibleSamples() []priceSample {
+	s.mu.Lock()
+	window := s.window
+	s.mu.Unlock()
+	return s.windowSamples(window)
+}
+
+// windowSamples returns, oldest first, the buffered samples within window,
+// regardless of which window the widget is currently displaying. Callers
+// that need a specific lookback (e.g. the portfolio line's 24h reference
+// price) independent of the user's chart toggle use this directly.
+func (s *SparklineWidget) windowSamples(window sparklineWindow) []priceSample {
+	s.mu.Lock()
+	samples := append([]priceSample(nil), s.samples...)
+	maxSamples := s.maxSamples
+	s.mu.Unlock()
+
+	if window == windowTicks {
+		if len(samples) > maxSamples {
+			samples = samples[len(samples)-maxSamples:]
+		}
+		return samples
+	}
+
+	cutoff := time.Now().Add(-windowDuration(window))
+	var visible []priceSample
+	for _, sample := range samples {
+		if sample.At.After(cutoff) {
+			visible = append(visible, sample)
+		}
+	}
+	return visible
+}
+
+// paintEvent draws a single antialiased polyline through every visible
+// sample, scaled to fill the widget and colored by net change vs. the
+// first visible sample.
+func (s *SparklineWidget) paintEvent(event *gui.QPaintEvent) {
+	samples := s.visibleSamples()
+
+	painter := gui.NewQPainter2(s)
+	defer painter.DestroyQPainter()
+	painter.SetRenderHint(gui.QPainter__Antialiasing, true)
+
+	if len(samples) < 2 {
+		return
+	}
+
+	minPrice, maxPrice := samples[0].Price, samples[0].Price
+	for _, sample := range samples {
+		if sample.Price.LessThan(minPrice) {
+			minPrice = sample.Price
+		}
+		if sample.Price.GreaterThan(maxPrice) {
+			maxPrice = sample.Price
+		}
+	}
+	span := maxPrice.Sub(minPrice)
+	if span.IsZero() {
+		span = decimal.NewFromInt(1)
+	}
+
+	lineColor := gui.NewQColor3(160, 160, 160, 255)
+	switch samples[len(samples)-1].Price.Cmp(samples[0].Price) {
+	case 1:
+		lineColor = gui.NewQColor3(0, 200, 0, 255)
+	case -1:
+		lineColor = gui.NewQColor3(200, 0, 0, 255)
+	}
+	pen := gui.NewQPen3(lineColor)
+	pen.SetWidthF(2)
+	painter.SetPen(pen)
+
+	width := float64(s.Width())
+	height := float64(s.Height())
+	step := width / float64(len(samples)-1)
+
+	var previous *core.QPointF
+	for i, sample := range samples {
+		frac, _ := sample.Price.Sub(minPrice).Div(span).Float64()
+		point := core.NewQPointF2(float64(i)*step, height-frac*height)
+		if previous != nil {
+			painter.DrawLine3(previous, point)
+		}
+		previous = point
+	}
+}
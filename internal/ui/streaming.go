@@ -0,0 +1,323 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/therecipe/qt/core"
+)
+
+// TickUpdate is a single price tick pushed by a Streamable provider.
+type TickUpdate struct {
+	Symbol string
+	Price  decimal.Decimal
+}
+
+// Streamable is implemented by providers that can push live price updates
+// over a persistent connection instead of being polled. CoinGeckoProvider
+// deliberately doesn't implement it: it's a REST-only index API, and rows
+// using only CoinGecko fall back to polling automatically.
+type Streamable interface {
+	// StreamTicks subscribes to the given symbols (quoted in quote) and
+	// writes every tick it receives to updates. It blocks, reconnecting
+	// internally with backoff, until ctx is canceled or reconnection is
+	// abandoned (after which it returns a non-nil error).
+	StreamTicks(ctx context.Context, symbols []string, quote string, updates chan<- TickUpdate) error
+}
+
+// streamReconnectLoop repeatedly dials a fresh connection and hands it to
+// run, applying exponential backoff between failed attempts. It only
+// returns once ctx is done. connect and run are provided by each exchange's
+// StreamTicks implementation; this just owns the reconnect policy.
+func streamReconnectLoop(ctx context.Context, name string, connect func(context.Context) (*websocket.Conn, error), run func(context.Context, *websocket.Conn) error) error {
+	const maxBackoff = 30 * time.Second
+	backoff := time.Second
+
+	for ctx.Err() == nil {
+		conn, err := connect(ctx)
+		if err != nil {
+			fmt.Printf("%s: stream connect failed: %v (retrying in %s)\n", name, err, backoff)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(jitter(backoff)):
+			}
+			if backoff < maxBackoff {
+				backoff *= 2
+			}
+			continue
+		}
+
+		backoff = time.Second
+		err = run(ctx, conn)
+		conn.Close()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fmt.Printf("%s: stream dropped: %v (reconnecting)\n", name, err)
+	}
+	return ctx.Err()
+}
+
+// heartbeat runs a ping/pong watchdog against conn: it sends a ping on
+// every tick of interval and fails the connection if no pong has arrived
+// since the previous ping, catching half-open sockets that never error
+// out. On failure it also closes conn itself, since the read loop that
+// would otherwise notice (e.g. each provider's StreamTicks ReadJSON loop in
+// providers.go) only checks
+// for a heartbeat failure between reads and would otherwise stay blocked
+// forever on the exact stuck socket this watchdog exists to catch.
+func heartbeat(ctx context.Context, conn *websocket.Conn, interval time.Duration) <-chan error {
+	failed := make(chan error, 1)
+	pongReceived := make(chan struct{}, 1)
+	conn.SetPongHandler(func(string) error {
+		select {
+		case pongReceived <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		awaitingPong := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if awaitingPong {
+					failed <- fmt.Errorf("heartbeat: no pong within %s", interval)
+					conn.Close()
+					return
+				}
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					failed <- fmt.Errorf("heartbeat: ping failed: %w", err)
+					conn.Close()
+					return
+				}
+				awaitingPong = true
+			case <-pongReceived:
+				awaitingPong = false
+			}
+		}
+	}()
+	return failed
+}
+
+// streamState tracks the GUI-visible state of one symbol's stream so the
+// manager can flash on direction changes and detect staleness.
+type streamState struct {
+	lastPrice decimal.Decimal
+	lastTick  time.Time
+}
+
+// StreamManager wires one or more Streamable providers into a BinanceWidget:
+// it fans tick updates from every provider into the widget's labels on the
+// GUI thread, flashes green/red on up/down ticks, and falls back to REST
+// polling for any symbol whose stream has been silent for too long.
+type StreamManager struct {
+	widget            *BinanceWidget
+	fallbackThreshold time.Duration
+
+	mu     sync.Mutex
+	states map[string]*streamState
+
+	cancel        context.CancelFunc
+	fallbackTimer *core.QTimer
+}
+
+// NewStreamManager creates a StreamManager for widget. fallbackThreshold is
+// how long a symbol may go without a tick before the manager starts polling
+// it over REST again.
+func NewStreamManager(widget *BinanceWidget, fallbackThreshold time.Duration) *StreamManager {
+	return &StreamManager{
+		widget:            widget,
+		fallbackThreshold: fallbackThreshold,
+		states:            make(map[string]*streamState),
+	}
+}
+
+// Start groups the widget's symbols by their primary streaming provider,
+// launches a goroutine per provider group, and starts the fallback poll
+// timer. It returns immediately; streaming runs until Stop is called.
+func (m *StreamManager) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	updates := make(chan TickUpdate, 64)
+	for provider, symbols := range m.groupByStreamProvider() {
+		go func(provider Streamable, symbols []string) {
+			// Each Streamable owns its own reconnect loop (see
+			// streamReconnectLoop) and only returns once ctx is canceled or
+			// it gives up reconnecting entirely.
+			if err := provider.StreamTicks(ctx, symbols, quoteCurrency, updates); err != nil && ctx.Err() == nil {
+				fmt.Printf("stream for %v ended: %v\n", symbols, err)
+			}
+		}(provider, symbols)
+	}
+
+	go m.consume(ctx, updates)
+
+	m.fallbackTimer = core.NewQTimer(nil)
+	m.fallbackTimer.ConnectTimeout(m.pollStaleSymbols)
+	m.fallbackTimer.Start(int(m.fallbackThreshold.Milliseconds() / 2))
+}
+
+// Stop tears down every streaming goroutine and the fallback poll timer
+// started by Start, so a manager replaced by dropSymbol/addSymbol/
+// applyConfig doesn't keep polling in the background forever.
+func (m *StreamManager) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.fallbackTimer != nil {
+		m.fallbackTimer.Stop()
+		m.fallbackTimer.DeleteLater()
+		m.fallbackTimer = nil
+	}
+}
+
+// groupByStreamProvider picks, for each symbol, the first configured
+// provider that implements Streamable, then groups symbols by that
+// provider so e.g. all Binance symbols share one websocket connection.
+func (m *StreamManager) groupByStreamProvider() map[Streamable][]string {
+	groups := make(map[Streamable][]string)
+	for symbol, providers := range m.widget.streamCandidates() {
+		for _, p := range providers {
+			if s, ok := p.(Streamable); ok {
+				groups[s] = append(groups[s], symbol)
+				break
+			}
+		}
+	}
+	return groups
+}
+
+// consume reads ticks off updates and applies them to the widget until ctx
+// is canceled.
+func (m *StreamManager) consume(ctx context.Context, updates <-chan TickUpdate) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-updates:
+			m.apply(tick)
+		}
+	}
+}
+
+// apply records the tick and marshals the resulting label update onto the
+// GUI thread, flashing the label green or red depending on direction.
+func (m *StreamManager) apply(tick TickUpdate) {
+	m.mu.Lock()
+	state, ok := m.states[tick.Symbol]
+	if !ok {
+		state = &streamState{}
+		m.states[tick.Symbol] = state
+	}
+	previous := state.lastPrice
+	state.lastPrice = tick.Price
+	state.lastTick = time.Now()
+	m.mu.Unlock()
+
+	direction := 0
+	if !previous.IsZero() {
+		direction = tick.Price.Cmp(previous)
+	}
+
+	core.QMetaObject_InvokeMethod4(m.widget, func() {
+		m.widget.applyTick(tick.Symbol, tick.Price, direction)
+	}, core.Qt__QueuedConnection)
+}
+
+// pollStaleSymbols falls back to the REST aggregator for any symbol whose
+// stream hasn't ticked within fallbackThreshold, e.g. because its
+// websocket is still reconnecting.
+func (m *StreamManager) pollStaleSymbols() {
+	now := time.Now()
+	var stale []string
+	m.mu.Lock()
+	for _, symbol := range m.widget.symbols {
+		state, ok := m.states[symbol]
+		if !ok || now.Sub(state.lastTick) > m.fallbackThreshold {
+			stale = append(stale, symbol)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, symbol := range stale {
+		go m.widget.updateLabel(symbol)
+	}
+}
+
+// streamCandidates returns, for each tracked symbol, its configured
+// providers in priority order, so the StreamManager can pick the first one
+// that supports streaming.
+func (w *BinanceWidget) streamCandidates() map[string][]PriceProvider {
+	candidates := make(map[string][]PriceProvider, len(w.symbolProviders))
+	for symbol, names := range w.symbolProviders {
+		for _, name := range names {
+			if p, ok := w.registry[name]; ok {
+				candidates[symbol] = append(candidates[symbol], p)
+			}
+		}
+	}
+	return candidates
+}
+
+// applyTick updates a symbol's label with a freshly streamed price and
+// flashes it green or red for a moment based on direction. It must only be
+// called on the GUI thread (see StreamManager.apply).
+func (w *BinanceWidget) applyTick(symbol string, price decimal.Decimal, direction int) {
+	label, ok := w.priceLabels[symbol]
+	if !ok {
+		return
+	}
+	label.SetText(fmt.Sprintf("%s/%s: %s", symbol, quoteCurrency, price.String()))
+	label.Font().SetPointSize(12)
+	if sparkline, ok := w.sparklines[symbol]; ok {
+		sparkline.Add(priceSample{At: time.Now(), Price: price})
+	}
+	w.refreshPortfolioLine(symbol, price)
+	w.alerts.CheckPrice(symbol, price)
+
+	switch {
+	case direction > 0:
+		w.flash(symbol, "rgb(0, 255, 0)")
+	case direction < 0:
+		w.flash(symbol, "rgb(255, 0, 0)")
+	}
+}
+
+// flash briefly sets symbol's label to flashColor, then restores the
+// widget's normal text color after flashDuration.
+func (w *BinanceWidget) flash(symbol, flashColor string) {
+	label, ok := w.priceLabels[symbol]
+	if !ok {
+		return
+	}
+	label.SetStyleSheet(fmt.Sprintf("color: %s", flashColor))
+
+	timer := core.NewQTimer(nil)
+	timer.SetSingleShot(true)
+	timer.ConnectTimeout(func() {
+		label.SetStyleSheet(w.baseLabelStyle)
+		timer.DeleteLater()
+	})
+	timer.Start(int(flashDuration.Milliseconds()))
+}
+
+const flashDuration = 600 * time.Millisecond
+
+// binanceStreamSymbol builds the lowercase `<symbol><quote>` stream name
+// Binance's combined stream endpoint expects, e.g. "btcusdt".
+func binanceStreamSymbol(symbol, quote string) string {
+	return strings.ToLower(symbol) + strings.ToLower(quote)
+}
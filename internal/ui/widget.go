@@ -0,0 +1,756 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/gui"
+	"github.com/therecipe/qt/widgets"
+
+	"github.com/zewebdev1337/price-tracker-widget/internal/config"
+	"github.com/zewebdev1337/price-tracker-widget/internal/portfolio"
+)
+
+// defaultProviderNames is the provider set used for a symbol that doesn't
+// explicitly list one in the config file.
+var defaultProviderNames = []string{"binance", "coinbase", "kraken", "coingecko"}
+
+// quoteCurrency is the currency every row is priced in. It's a constant for
+// now; per-row quote currencies can follow once there's demand for it.
+const quoteCurrency = "USDT"
+
+type BinanceWidget struct {
+	widgets.QWidget
+
+	// registry holds one instance of every known PriceProvider, looked up
+	// by name when resolving a row's provider list.
+	registry map[string]PriceProvider
+
+	// aggregators holds the per-symbol Aggregator built from that symbol's
+	// configured providers, so different rows can mix CEX and index sources.
+	aggregators map[string]*Aggregator
+
+	// symbolProviders holds each symbol's configured provider names in
+	// priority order, as given in the config file (or defaultProviderNames).
+	// The StreamManager uses it to pick each symbol's streaming provider.
+	symbolProviders map[string][]string
+
+	// streamManager drives live websocket updates for streaming-capable
+	// providers, falling back to aggregators for the rest.
+	streamManager *StreamManager
+
+	// baseLabelStyle is the non-flashing style sheet applied to price
+	// labels; mouseDoubleClickEvent toggles it and flash() restores it.
+	baseLabelStyle string
+
+	// priceLabels stores pointers to QLabel widgets.
+	// Each QLabel widget displays the price of a specific pair.
+	// The key of the map is the symbol string, and the value is a pointer to the corresponding QLabel widget.
+	priceLabels map[string]*widgets.QLabel
+
+	// sparklines holds each symbol's SparklineWidget, updated alongside its
+	// label in updateLabel and applyTick.
+	sparklines map[string]*SparklineWidget
+
+	// rows holds the per-symbol container widget (label + sparkline) added
+	// to layout, so symbolAt and removeSymbol can operate on the whole row
+	// instead of just the label.
+	rows map[string]*widgets.QWidget
+
+	// symbols is a slice that stores the symbols to be tracked.
+	symbols []string
+
+	// oldPos is a pointer to a QPoint object that stores the previous position of the mouse cursor.
+	// This is used to calculate the distance moved by the mouse cursor and update the position of the widget accordingly.
+	oldPos *core.QPoint
+
+	// layout is a pointer to a QVBoxLayout object that manages the layout of the widgets in the BinanceWidget.
+	layout *widgets.QVBoxLayout
+
+	// providerOverrides holds each symbol's raw Providers list exactly as
+	// read from the config file (nil when the row uses the default set),
+	// so saving the symbol list back out doesn't bake today's defaults in.
+	providerOverrides map[string][]string
+
+	// alerts manages the price alerts configured for any tracked symbol
+	// and fires a desktop notification when one triggers.
+	alerts *AlertManager
+
+	// tray is the system tray icon alerts use to show notifications.
+	tray *widgets.QSystemTrayIcon
+
+	// portfolio holds the holdings configured for any tracked symbol and
+	// the FX rates needed to value them.
+	portfolio *portfolio.Portfolio
+
+	// portfolioLabels holds the per-symbol line showing qty x price =
+	// value plus P/L, below that row's price label. It's hidden for
+	// symbols with no holding.
+	portfolioLabels map[string]*widgets.QLabel
+
+	// totalLabel shows the portfolio-wide value and P/L across every
+	// holding. It's hidden when no symbol has a holding.
+	totalLabel *widgets.QLabel
+
+	// lastPrices holds each symbol's most recently seen price, so an
+	// edited holding can be valued immediately instead of waiting for the
+	// next tick.
+	lastPrices map[string]decimal.Decimal
+
+	// configWatcher reloads symbols and alerts from the shared config file
+	// when another process (e.g. a second widget instance) changes it, so
+	// both stay in sync without a restart.
+	configWatcher *ConfigWatcher
+
+	// sparklineSamples is how many samples a "last N ticks" sparkline
+	// keeps, read from config.UISettings.SparklineSamples and falling
+	// back to defaultSparklineSamples when unset.
+	sparklineSamples int
+}
+
+// NewBinanceWidget creates a new instance of BinanceWidget with the given parent, window type, symbol configs,
+// and UI preferences.
+// It initializes the widget's provider registry, per-symbol aggregators, price labels, symbols, and old position.
+// It then calls the initUI method to set up the widget's UI.
+func NewBinanceWidget(parent widgets.QWidget_ITF, fo core.Qt__WindowType, configs []config.SymbolConfig, ui config.UISettings) *BinanceWidget {
+	registry := newProviderRegistry()
+
+	sparklineSamples := ui.SparklineSamples
+	if sparklineSamples <= 0 {
+		sparklineSamples = defaultSparklineSamples
+	}
+
+	symbols := make([]string, 0, len(configs))
+	aggregators := make(map[string]*Aggregator, len(configs))
+	symbolProviders := make(map[string][]string, len(configs))
+	providerOverrides := make(map[string][]string, len(configs))
+	holdings := make(map[string]portfolio.Holding, len(configs))
+	for _, cfg := range configs {
+		symbols = append(symbols, cfg.Symbol)
+		aggregators[cfg.Symbol] = buildAggregator(registry, cfg.Providers)
+		providerOverrides[cfg.Symbol] = cfg.Providers
+		names := cfg.Providers
+		if len(names) == 0 {
+			names = defaultProviderNames
+		}
+		symbolProviders[cfg.Symbol] = names
+		if cfg.Holding != nil {
+			holdings[cfg.Symbol] = *cfg.Holding
+		}
+	}
+
+	widget := &BinanceWidget{
+		QWidget:           *widgets.NewQWidget(parent, fo),
+		registry:          registry,
+		aggregators:       aggregators,
+		symbolProviders:   symbolProviders,
+		providerOverrides: providerOverrides,
+		baseLabelStyle:    "color: rgb(0, 255, 0)",
+		oldPos:            core.NewQPoint(),
+		priceLabels:       make(map[string]*widgets.QLabel),
+		sparklines:        make(map[string]*SparklineWidget, len(configs)),
+		rows:              make(map[string]*widgets.QWidget, len(configs)),
+		symbols:           symbols,
+		portfolio:         portfolio.New(quoteCurrency, holdings),
+		portfolioLabels:   make(map[string]*widgets.QLabel, len(configs)),
+		lastPrices:        make(map[string]decimal.Decimal, len(configs)),
+		sparklineSamples:  sparklineSamples,
+	}
+	widget.initUI()
+	return widget
+}
+
+// newProviderRegistry builds the set of all providers the widget knows
+// about, keyed by their Name().
+func newProviderRegistry() map[string]PriceProvider {
+	providers := []PriceProvider{
+		NewBinanceProvider(),
+		NewCoinbaseProvider(),
+		NewKrakenProvider(),
+		NewCoinGeckoProvider(),
+	}
+	registry := make(map[string]PriceProvider, len(providers))
+	for _, p := range providers {
+		registry[p.Name()] = p
+	}
+	return registry
+}
+
+// buildAggregator resolves provider names to registry entries and wraps
+// them in an Aggregator. Unknown names are skipped rather than failing the
+// whole row, since a typo in one provider shouldn't take down the symbol.
+func buildAggregator(registry map[string]PriceProvider, names []string) *Aggregator {
+	if len(names) == 0 {
+		names = defaultProviderNames
+	}
+	providers := make([]PriceProvider, 0, len(names))
+	for _, name := range names {
+		if p, ok := registry[name]; ok {
+			providers = append(providers, p)
+		} else {
+			fmt.Printf("Unknown price provider %q, skipping\n", name)
+		}
+	}
+	return NewAggregator(providers, 5*time.Second, 2, StrategyMedian)
+}
+
+// validateProviderSupport warns about any row explicitly configured for a
+// provider that doesn't actually list that row's symbol among the ones it
+// supports -- most likely a typo'd symbol or a provider that doesn't list
+// the asset at all. It calls every registered provider's SupportedSymbols
+// once regardless of how many rows use it, and is best-effort: a
+// provider that fails to answer (e.g. a network error) is skipped rather
+// than treated as unsupporting everything.
+func (w *BinanceWidget) validateProviderSupport() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	supported := make(map[string]map[string]bool, len(w.registry))
+	for name, provider := range w.registry {
+		symbols, err := provider.SupportedSymbols(ctx)
+		if err != nil {
+			continue
+		}
+		set := make(map[string]bool, len(symbols))
+		for _, symbol := range symbols {
+			set[strings.ToUpper(symbol)] = true
+		}
+		supported[name] = set
+	}
+
+	for symbol, names := range w.symbolProviders {
+		for _, name := range names {
+			set, ok := supported[name]
+			if !ok {
+				continue
+			}
+			if !set[strings.ToUpper(symbol)] {
+				fmt.Printf("%s: provider %q does not list this symbol as supported\n", symbol, name)
+			}
+		}
+	}
+}
+
+// initUI sets up the UI for the BinanceWidget.
+// It sets the window flags and attributes, creates a new vertical box layout, and adds a row (price label plus
+// sparkline) for each symbol.
+// It then sets the layout for the widget, does an initial price fetch, backfills each sparkline from history, and
+// starts the streaming subsystem, which keeps prices updated afterwards and polls over REST only for symbols whose
+// stream has gone stale.
+// It also connects the mouse press, move, and context menu events to their respective methods.
+func (w *BinanceWidget) initUI() {
+	w.SetWindowFlags(core.Qt__FramelessWindowHint | core.Qt__WindowStaysOnTopHint)
+	w.SetAttribute(core.Qt__WA_X11NetWmWindowTypeDock, true)
+	w.SetAttribute(core.Qt__WA_TranslucentBackground, true)
+	w.layout = widgets.NewQVBoxLayout()
+
+	for _, symbol := range w.symbols {
+		w.addRow(symbol)
+	}
+
+	w.totalLabel = widgets.NewQLabel2("", nil, 0)
+	w.totalLabel.SetStyleSheet(w.baseLabelStyle)
+	w.totalLabel.SetVisible(false)
+	w.layout.AddWidget(w.totalLabel, 0, 0)
+
+	w.SetLayout(w.layout)
+
+	w.tray = widgets.NewQSystemTrayIcon(nil)
+	w.tray.Show()
+	w.alerts = NewAlertManager(w.tray)
+
+	w.updatePrice()
+	w.backfillSparklines()
+	w.backfillFXRates()
+	go w.validateProviderSupport()
+
+	w.streamManager = NewStreamManager(w, 90*time.Second)
+	w.streamManager.Start()
+
+	if watcher, err := NewConfigWatcher(w); err != nil {
+		fmt.Println("Error starting config watcher:", err)
+	} else {
+		w.configWatcher = watcher
+		w.configWatcher.Start()
+	}
+
+	w.ConnectMousePressEvent(w.mousePressEvent)
+	w.ConnectMouseMoveEvent(w.mouseMoveEvent)
+	w.ConnectMouseDoubleClickEvent(w.mouseDoubleClickEvent)
+	w.ConnectContextMenuEvent(w.contextMenuEvent)
+}
+
+// addRow builds the row widget for symbol: a price label beside a
+// SparklineWidget, plus a second line beneath them showing that symbol's
+// portfolio position (hidden until a holding is configured for it), all
+// stacked vertically and added to w.layout.
+func (w *BinanceWidget) addRow(symbol string) {
+	label := widgets.NewQLabel2("Loading...", nil, 0)
+	label.SetStyleSheet(w.baseLabelStyle)
+
+	sparkline := NewSparklineWidget(nil, w.sparklineSamples)
+
+	priceLayout := widgets.NewQHBoxLayout()
+	priceLayout.AddWidget(label, 0, 0)
+	priceLayout.AddWidget(sparkline, 0, 0)
+
+	portfolioLabel := widgets.NewQLabel2("", nil, 0)
+	portfolioLabel.SetStyleSheet(w.baseLabelStyle)
+	portfolioLabel.SetVisible(false)
+
+	rowLayout := widgets.NewQVBoxLayout()
+	rowLayout.AddLayout(priceLayout, 0)
+	rowLayout.AddWidget(portfolioLabel, 0, 0)
+
+	row := widgets.NewQWidget(nil, 0)
+	row.SetLayout(rowLayout)
+	w.layout.AddWidget(row, 0, 0)
+
+	w.priceLabels[symbol] = label
+	w.sparklines[symbol] = sparkline
+	w.portfolioLabels[symbol] = portfolioLabel
+	w.rows[symbol] = row
+}
+
+// backfillSparklines seeds every row's sparkline with recent history from
+// the first of that symbol's configured providers that implements
+// HistoricalProvider, so the chart isn't empty while waiting for live
+// ticks. Symbols with no such provider (e.g. CoinGecko-only rows) just
+// start empty and fill in as prices arrive.
+func (w *BinanceWidget) backfillSparklines() {
+	for _, symbol := range w.symbols {
+		go w.backfillSparkline(symbol)
+	}
+}
+
+// backfillSparkline fetches symbol's recent klines, if any configured
+// provider can supply them, and seeds its sparkline with the result.
+func (w *BinanceWidget) backfillSparkline(symbol string) {
+	provider := w.historicalProvider(symbol)
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	samples, err := provider.Klines(ctx, symbol, quoteCurrency, time.Minute, w.sparklineSamples)
+	if err != nil {
+		fmt.Printf("%s: backfill sparkline failed: %v\n", symbol, err)
+		return
+	}
+	core.QMetaObject_InvokeMethod4(w, func() {
+		if sparkline, ok := w.sparklines[symbol]; ok {
+			sparkline.Seed(samples)
+		}
+	}, core.Qt__QueuedConnection)
+}
+
+// historicalProvider returns the first of symbol's configured providers
+// that implements HistoricalProvider, or nil if none do.
+func (w *BinanceWidget) historicalProvider(symbol string) HistoricalProvider {
+	for _, name := range w.symbolProviders[symbol] {
+		p, ok := w.registry[name]
+		if !ok {
+			continue
+		}
+		if hp, ok := p.(HistoricalProvider); ok {
+			return hp
+		}
+	}
+	return nil
+}
+
+// backfillFXRates refreshes the FX rate for every cost currency in use
+// across the widget's holdings that isn't already quoteCurrency, so those
+// rows don't value at their raw quoteCurrency price until the next refresh.
+func (w *BinanceWidget) backfillFXRates() {
+	currencies := make(map[string]bool)
+	for _, h := range w.portfolio.List() {
+		if currency := h.CostCurrency(quoteCurrency); currency != quoteCurrency {
+			currencies[currency] = true
+		}
+	}
+	for currency := range currencies {
+		go w.refreshFXRate(currency)
+	}
+}
+
+// refreshFXRate fetches the current quoteCurrency->currency rate by
+// quoting currency itself as a symbol against quoteCurrency (e.g. EUR
+// against USDT), using the widget's default provider set, inverting that
+// price, and storing the result on the portfolio.
+func (w *BinanceWidget) refreshFXRate(currency string) {
+	if currency == "" || currency == quoteCurrency {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	price, err := buildAggregator(w.registry, nil).Fetch(ctx, currency, quoteCurrency)
+	if err != nil {
+		fmt.Printf("%s: refresh FX rate failed: %v\n", currency, err)
+		return
+	}
+	if price.IsZero() {
+		fmt.Printf("%s: refresh FX rate failed: zero price against %s\n", currency, quoteCurrency)
+		return
+	}
+	w.portfolio.SetFXRate(currency, decimal.NewFromInt(1).Div(price))
+	core.QMetaObject_InvokeMethod4(w, func() {
+		w.refreshAllPortfolioLines()
+	}, core.Qt__QueuedConnection)
+}
+
+// refreshAllPortfolioLines recomputes every symbol's portfolio line from
+// its last known price, e.g. after an FX rate refresh changes every
+// foreign-currency holding's value at once.
+func (w *BinanceWidget) refreshAllPortfolioLines() {
+	for symbol, price := range w.lastPrices {
+		w.refreshPortfolioLine(symbol, price)
+	}
+}
+
+// refreshPortfolioLine updates symbol's portfolio line (and the portfolio
+// total) from its latest price. It hides the line if symbol has no
+// holding. It must only be called on the GUI thread.
+func (w *BinanceWidget) refreshPortfolioLine(symbol string, price decimal.Decimal) {
+	w.lastPrices[symbol] = price
+
+	label, ok := w.portfolioLabels[symbol]
+	if !ok {
+		return
+	}
+	holding, ok := w.portfolio.Get(symbol)
+	if !ok {
+		label.SetVisible(false)
+		w.refreshTotalLabel()
+		return
+	}
+
+	windowPrice := price
+	if sparkline, ok := w.sparklines[symbol]; ok {
+		if samples := sparkline.windowSamples(window24h); len(samples) > 0 {
+			windowPrice = samples[0].Price
+		}
+	}
+
+	fxRate := w.portfolio.FXRate(holding.CostCurrency(quoteCurrency))
+	pv := portfolio.ValuePosition(holding, price, fxRate, windowPrice)
+
+	label.SetText(fmt.Sprintf("%s %s @ %s = %s %s | P/L %s %s (%s%%) | 24h %s %s (%s%%)",
+		holding.Amount.String(), symbol, price.String(),
+		pv.Value.String(), holding.CostCurrency(quoteCurrency),
+		pv.PL.String(), holding.CostCurrency(quoteCurrency), pv.PLPercent.StringFixed(2),
+		pv.WindowPL.String(), holding.CostCurrency(quoteCurrency), pv.WindowPLPercent.StringFixed(2)))
+	label.SetVisible(true)
+
+	w.refreshTotalLabel()
+}
+
+// refreshTotalLabel recomputes the portfolio-wide footer from every
+// symbol's last known price, hiding it entirely when nothing is held.
+func (w *BinanceWidget) refreshTotalLabel() {
+	var values []portfolio.PositionValue
+	currency := ""
+	mixed := false
+	for symbol, holding := range w.portfolio.List() {
+		price, ok := w.lastPrices[symbol]
+		if !ok {
+			continue
+		}
+		windowPrice := price
+		if sparkline, ok := w.sparklines[symbol]; ok {
+			if samples := sparkline.windowSamples(window24h); len(samples) > 0 {
+				windowPrice = samples[0].Price
+			}
+		}
+		fxRate := w.portfolio.FXRate(holding.CostCurrency(quoteCurrency))
+		values = append(values, portfolio.ValuePosition(holding, price, fxRate, windowPrice))
+
+		switch {
+		case currency == "":
+			currency = holding.CostCurrency(quoteCurrency)
+		case currency != holding.CostCurrency(quoteCurrency):
+			mixed = true
+		}
+	}
+
+	if len(values) == 0 {
+		w.totalLabel.SetVisible(false)
+		return
+	}
+	if mixed {
+		// Holdings costed in different currencies can't be summed into one
+		// figure; tell the user instead of silently adding incompatible units.
+		currency = "mixed currencies, not summed"
+		w.totalLabel.SetText(fmt.Sprintf("Portfolio: %d holdings in %s", len(values), currency))
+		w.totalLabel.SetVisible(true)
+		return
+	}
+
+	total := portfolio.SumPositions(values)
+	w.totalLabel.SetText(fmt.Sprintf("Portfolio: %s %s | P/L %s %s (%s%%) | 24h %s %s (%s%%)",
+		total.Value.String(), currency,
+		total.PL.String(), currency, total.PLPercent.StringFixed(2),
+		total.WindowPL.String(), currency, total.WindowPLPercent.StringFixed(2)))
+	w.totalLabel.SetVisible(true)
+}
+
+// updatePrice asks each symbol's Aggregator for a fresh price via updateLabel.
+// It uses a WaitGroup to ensure that all requests have completed before returning.
+// Each request is made in a separate goroutine.
+func (w *BinanceWidget) updatePrice() {
+	var wg sync.WaitGroup
+	for _, symbol := range w.symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			w.updateLabel(symbol)
+		}(symbol)
+	}
+	wg.Wait()
+}
+
+// updateLabel asks that symbol's Aggregator for an up-to-date price and
+// updates the corresponding QLabel widget with it. The aggregator already
+// handles querying multiple providers, retries, and failover, so a failure
+// here means every configured provider for this symbol is unavailable.
+func (w *BinanceWidget) updateLabel(symbol string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	price, err := w.aggregators[symbol].Fetch(ctx, symbol, quoteCurrency)
+	if err != nil {
+		core.QMetaObject_InvokeMethod4(w, func() {
+			w.priceLabels[symbol].SetText(fmt.Sprintf("Error: %v", err))
+		}, core.Qt__QueuedConnection)
+		return
+	}
+	core.QMetaObject_InvokeMethod4(w, func() {
+		w.priceLabels[symbol].SetText(fmt.Sprintf("%s/%s: %s", symbol, quoteCurrency, price.String()))
+		w.priceLabels[symbol].Font().SetPointSize(12)
+		if sparkline, ok := w.sparklines[symbol]; ok {
+			sparkline.Add(priceSample{At: time.Now(), Price: price})
+		}
+		w.refreshPortfolioLine(symbol, price)
+		w.alerts.CheckPrice(symbol, price)
+	}, core.Qt__QueuedConnection)
+}
+
+// mousePressEvent is called when the mouse button is pressed on the widget.
+// It updates the oldPos field with the current position of the mouse cursor.
+func (w *BinanceWidget) mousePressEvent(event *gui.QMouseEvent) {
+	w.oldPos = event.GlobalPos()
+}
+
+// mouseMoveEvent is called when the mouse cursor is moved over the widget.
+// It calculates the distance moved by the mouse cursor and updates the position of the widget accordingly.
+// It also updates the oldPos field with the current position of the mouse cursor.
+func (w *BinanceWidget) mouseMoveEvent(event *gui.QMouseEvent) {
+	deltaX := event.GlobalPos().X() - w.oldPos.X()
+	deltaY := event.GlobalPos().Y() - w.oldPos.Y()
+	w.Move2(w.X()+deltaX, w.Y()+deltaY)
+	w.oldPos = event.GlobalPos()
+}
+
+// mouseDoubleClickEvent toggles the color of the text in all price labels
+// to its inverse. This used to live on right-click, but that's now the
+// alerts/remove-symbol context menu below.
+func (w *BinanceWidget) mouseDoubleClickEvent(event *gui.QMouseEvent) {
+	if len(w.symbols) == 0 {
+		return
+	}
+	currentColor := w.priceLabels[w.symbols[0]].Palette().Color(gui.QPalette__Active, gui.QPalette__WindowText)
+	newColor := gui.NewQColor3(255-currentColor.Red(), 255-currentColor.Green(), 255-currentColor.Blue(), 255)
+
+	// Create a new style sheet string with the new color
+	styleSheet := fmt.Sprintf("color: rgb(%d, %d, %d)", newColor.Red(), newColor.Green(), newColor.Blue())
+	// Set the style sheet of all price labels to the new style sheet
+	w.baseLabelStyle = styleSheet
+	for _, label := range w.priceLabels {
+		label.SetStyleSheet(styleSheet)
+		label.Font().SetPointSize(12)
+	}
+}
+
+// contextMenuEvent is called when a context menu event is triggered on the
+// widget. It offers alert management for the row under the cursor plus the
+// option to stop tracking that symbol altogether.
+func (w *BinanceWidget) contextMenuEvent(event *gui.QContextMenuEvent) {
+	symbol := w.symbolAt(event.Pos())
+
+	menu := widgets.NewQMenu(nil)
+
+	addAlert := menu.AddAction("Add alert…")
+	addAlert.SetEnabled(symbol != "")
+	addAlert.ConnectTriggered(func(checked bool) {
+		w.showAddAlertDialog(symbol)
+	})
+
+	manageAlerts := menu.AddAction("Manage alerts…")
+	manageAlerts.ConnectTriggered(func(checked bool) {
+		w.showManageAlertsDialog()
+	})
+
+	editHoldings := menu.AddAction("Edit holdings…")
+	editHoldings.SetEnabled(symbol != "")
+	editHoldings.ConnectTriggered(func(checked bool) {
+		w.showEditHoldingsDialog(symbol)
+	})
+
+	if sparkline, ok := w.sparklines[symbol]; ok {
+		windowMenu := menu.AddMenu("Sparkline window")
+		current := sparkline.Window()
+		for _, window := range sparklineWindows {
+			window := window
+			toggle := windowMenu.AddAction(window.String())
+			toggle.SetCheckable(true)
+			toggle.SetChecked(window == current)
+			toggle.ConnectTriggered(func(checked bool) {
+				sparkline.SetWindow(window)
+			})
+		}
+	}
+
+	removeSymbol := menu.AddAction("Remove symbol")
+	removeSymbol.SetEnabled(symbol != "")
+	removeSymbol.ConnectTriggered(func(checked bool) {
+		w.removeSymbol(symbol)
+	})
+
+	menu.Exec2(event.GlobalPos(), nil)
+}
+
+// symbolAt returns the symbol whose row contains pos, or "" if pos falls
+// outside every row, e.g. the menu was opened in the window margin.
+func (w *BinanceWidget) symbolAt(pos *core.QPoint) string {
+	for _, symbol := range w.symbols {
+		if row, ok := w.rows[symbol]; ok && row.Geometry().Contains2(pos.X(), pos.Y(), false) {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// removeSymbol stops tracking symbol and persists the updated symbol list.
+func (w *BinanceWidget) removeSymbol(symbol string) {
+	if !w.dropSymbol(symbol) {
+		return
+	}
+	if err := config.SaveSymbols(w.currentConfigs()); err != nil {
+		fmt.Println("Error saving symbols:", err)
+	}
+}
+
+// dropSymbol removes symbol's row and in-memory state and restarts the
+// StreamManager so it regroups around the remaining symbols. It reports
+// whether symbol was tracked. It doesn't persist the change -- removeSymbol
+// does, since it's acting on the user's request; applyConfig doesn't,
+// since it's reconciling state that's already on disk.
+func (w *BinanceWidget) dropSymbol(symbol string) bool {
+	row, ok := w.rows[symbol]
+	if !ok {
+		return false
+	}
+
+	w.layout.RemoveWidget(row)
+	row.DeleteLater()
+	delete(w.priceLabels, symbol)
+	delete(w.sparklines, symbol)
+	delete(w.portfolioLabels, symbol)
+	delete(w.rows, symbol)
+	delete(w.aggregators, symbol)
+	delete(w.symbolProviders, symbol)
+	delete(w.providerOverrides, symbol)
+	delete(w.lastPrices, symbol)
+	w.portfolio.Remove(symbol)
+	w.refreshTotalLabel()
+	for i, s := range w.symbols {
+		if s == symbol {
+			w.symbols = append(w.symbols[:i], w.symbols[i+1:]...)
+			break
+		}
+	}
+
+	w.streamManager.Stop()
+	w.streamManager = NewStreamManager(w, 90*time.Second)
+	w.streamManager.Start()
+	return true
+}
+
+// addSymbol starts tracking cfg.Symbol at runtime: it wires up its
+// aggregator and provider list, adds its row to the layout, backfills its
+// sparkline, and restarts the StreamManager so streaming regroups around
+// the new symbol. It's a no-op if the symbol is already tracked. Like
+// dropSymbol, it doesn't persist -- callers that originate the change
+// (none yet; today only applyConfig calls this) are responsible for that.
+func (w *BinanceWidget) addSymbol(cfg config.SymbolConfig) {
+	if _, exists := w.aggregators[cfg.Symbol]; exists {
+		return
+	}
+
+	w.aggregators[cfg.Symbol] = buildAggregator(w.registry, cfg.Providers)
+	w.providerOverrides[cfg.Symbol] = cfg.Providers
+	names := cfg.Providers
+	if len(names) == 0 {
+		names = defaultProviderNames
+	}
+	w.symbolProviders[cfg.Symbol] = names
+	if cfg.Holding != nil {
+		w.portfolio.Set(cfg.Symbol, *cfg.Holding)
+		if currency := cfg.Holding.CostCurrency(quoteCurrency); currency != quoteCurrency {
+			go w.refreshFXRate(currency)
+		}
+	}
+	w.symbols = append(w.symbols, cfg.Symbol)
+
+	w.addRow(cfg.Symbol)
+	go w.backfillSparkline(cfg.Symbol)
+
+	w.streamManager.Stop()
+	w.streamManager = NewStreamManager(w, 90*time.Second)
+	w.streamManager.Start()
+}
+
+// applyConfig reconciles the widget's running state with cf: it adds rows
+// for symbols cf has that the widget doesn't, removes rows for symbols the
+// widget has that cf doesn't, and replaces the in-memory alert list. It
+// never persists, since cf was just read from the config file this state
+// is reconciling against. It must only be called on the GUI thread.
+func (w *BinanceWidget) applyConfig(cf config.ConfigFile) {
+	want := make(map[string]config.SymbolConfig, len(cf.Symbols))
+	for _, cfg := range cf.Symbols {
+		want[cfg.Symbol] = cfg
+	}
+
+	for _, symbol := range append([]string(nil), w.symbols...) {
+		if _, ok := want[symbol]; !ok {
+			w.dropSymbol(symbol)
+		}
+	}
+	for _, cfg := range cf.Symbols {
+		w.addSymbol(cfg)
+	}
+
+	w.alerts.Reload(cf.Alerts)
+}
+
+// currentConfigs returns the widget's current rows as config.SymbolConfigs,
+// suitable for persisting back to the config file.
+func (w *BinanceWidget) currentConfigs() []config.SymbolConfig {
+	configs := make([]config.SymbolConfig, 0, len(w.symbols))
+	for _, symbol := range w.symbols {
+		cfg := config.SymbolConfig{Symbol: symbol, Providers: w.providerOverrides[symbol]}
+		if h, ok := w.portfolio.Get(symbol); ok {
+			cfg.Holding = &h
+		}
+		configs = append(configs, cfg)
+	}
+	return configs
+}